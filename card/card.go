@@ -1,6 +1,8 @@
 // Package card defines cards for playing Cassino.
 package card
 
+import "fmt"
+
 // A Card is a playing card in a standard 52-card deck. Cards are ordered first
 // by rank, then by suit in the order clubs, diamonds, hearts, spades (e.g. ace
 // of clubs = 0, ace of diamonds = 1, king of spades = 51).
@@ -14,19 +16,141 @@ const (
 	BigCassino Card = 37
 )
 
-// Rank returns a card's rank. Face cards have ranks 11-13.
-func (c Card) Rank() int { return int(c/4 + 1) }
+// A Suit is one of the four suits.
+type Suit int
+
+// The suits, in card order.
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+// A Rank is one of the thirteen ranks. Face cards have ranks 11-13.
+type Rank int
+
+// The ranks, in card order.
+const (
+	Ace Rank = iota + 1
+	Two
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+)
+
+var (
+	suitNames = []string{"Clubs", "Diamonds", "Hearts", "Spades"}
+	rankNames = []string{"Ace", "Two", "Three", "Four", "Five", "Six", "Seven",
+		"Eight", "Nine", "Ten", "Jack", "Queen", "King"}
+)
+
+// Valid reports whether s is one of the four suits.
+func (s Suit) Valid() bool { return s >= Clubs && s <= Spades }
+
+// String returns the symbol for a suit, as in "♠".
+func (s Suit) String() string {
+	if !s.Valid() {
+		return fmt.Sprintf("Suit(%d)", int(s))
+	}
+	return `♣♦♥♠`[s*3 : s*3+3]
+}
+
+// Name returns the name of a suit, as in "Spades".
+func (s Suit) Name() string {
+	if !s.Valid() {
+		return s.String()
+	}
+	return suitNames[s]
+}
+
+// Valid reports whether r is one of the thirteen ranks.
+func (r Rank) Valid() bool { return r >= Ace && r <= King }
+
+// String returns the character for a rank, as in "A" or "T".
+func (r Rank) String() string {
+	if !r.Valid() {
+		return fmt.Sprintf("Rank(%d)", int(r))
+	}
+	return ranks[r-1 : r]
+}
+
+// Name returns the name of a rank, as in "Ace" or "Ten".
+func (r Rank) Name() string {
+	if !r.Valid() {
+		return r.String()
+	}
+	return rankNames[r-1]
+}
+
+// New returns the card of rank r and suit s. If either is not valid, New
+// returns a Card that is not Valid.
+func New(r Rank, s Suit) Card {
+	if !r.Valid() || !s.Valid() {
+		return -1
+	}
+	return Card(int(r-1)*4 + int(s))
+}
+
+// Valid reports whether c is one of the 52 cards.
+func (c Card) Valid() bool { return c >= 0 && c < 52 }
+
+// Rank returns a card's rank, or 0 if c is not Valid.
+func (c Card) Rank() Rank {
+	if !c.Valid() {
+		return 0
+	}
+	return Rank(c/4 + 1)
+}
+
+// Suit returns a card's suit, or -1 if c is not Valid.
+func (c Card) Suit() Suit {
+	if !c.Valid() {
+		return -1
+	}
+	return Suit(c % 4)
+}
 
 // IsAce reports whether a card is an ace.
-func (c Card) IsAce() bool { return c.Rank() == 1 }
+func (c Card) IsAce() bool { return c.Rank() == Ace }
 
 // IsFace reports whether a card is a face card.
-func (c Card) IsFace() bool { return c.Rank() > 10 }
+func (c Card) IsFace() bool { return c.Rank() >= Jack }
 
 // IsSpade reports whether a card is a spade.
-func (c Card) IsSpade() bool { return c%4 == 3 }
+func (c Card) IsSpade() bool { return c.Suit() == Spades }
+
+// SameRank reports whether c and d are valid cards of the same rank.
+func (c Card) SameRank(d Card) bool { return c.Valid() && c.Rank() == d.Rank() }
 
-// String returns a string representation of a card.
+// SameSuit reports whether c and d are valid cards of the same suit.
+func (c Card) SameSuit(d Card) bool { return c.Valid() && c.Suit() == d.Suit() }
+
+// Less reports whether c comes before d in card order: lower rank first, then
+// suit in the order clubs, diamonds, hearts, spades.
+func (c Card) Less(d Card) bool { return c < d }
+
+// String returns a string representation of a card, as in "♠2". A card that
+// is not Valid is written as its number, as in "Card(52)".
 func (c Card) String() string {
-	return `♣♦♥♠`[c%4*3:c%4*3+3] + `A23456789TJQK`[c.Rank()-1:c.Rank()]
+	if !c.Valid() {
+		return fmt.Sprintf("Card(%d)", int(c))
+	}
+	return c.Suit().String() + c.Rank().String()
+}
+
+// Name returns the name of a card, as in "Ten of Diamonds".
+func (c Card) Name() string {
+	if !c.Valid() {
+		return c.String()
+	}
+	return c.Rank().Name() + " of " + c.Suit().Name()
 }
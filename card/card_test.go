@@ -1,11 +1,12 @@
 package card
 
 import (
+	"fmt"
 	"testing"
 )
 
 var cardTests = []struct {
-	rank                   int
+	rank                   Rank
 	isAce, isFace, isSpade bool
 	s                      string
 }{
@@ -93,3 +94,110 @@ func TestCard(t *testing.T) {
 		}
 	}
 }
+
+func TestNew(t *testing.T) {
+	for i := range cardTests {
+		c := Card(i)
+		if got := New(c.Rank(), c.Suit()); got != c {
+			t.Errorf("New(%v, %v): got %v, expected %v", c.Rank(), c.Suit(), got, c)
+		}
+		if !c.Valid() {
+			t.Errorf("Valid(%v): got false, expected true", c)
+		}
+	}
+	for _, test := range []struct {
+		r Rank
+		s Suit
+	}{
+		{0, Clubs},
+		{King + 1, Spades},
+		{Ace, -1},
+		{Ace, Spades + 1},
+	} {
+		if c := New(test.r, test.s); c.Valid() {
+			t.Errorf("New(%d, %d): got valid card %v", int(test.r), int(test.s), c)
+		}
+	}
+}
+
+var nameTests = []struct {
+	c    Card
+	s    string
+	name string
+}{
+	{0, "♣A", "Ace of Clubs"},
+	{LittleCassino, "♠2", "Two of Spades"},
+	{BigCassino, "♦T", "Ten of Diamonds"},
+	{New(Queen, Hearts), "♥Q", "Queen of Hearts"},
+	{51, "♠K", "King of Spades"},
+	{-1, "Card(-1)", "Card(-1)"},
+	{52, "Card(52)", "Card(52)"},
+}
+
+func TestName(t *testing.T) {
+	for _, test := range nameTests {
+		if s := test.c.String(); s != test.s {
+			t.Errorf("String(%d): got %v, expected %v", int(test.c), s, test.s)
+		}
+		if name := test.c.Name(); name != test.name {
+			t.Errorf("Name(%d): got %v, expected %v", int(test.c), name, test.name)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, c := range []Card{-1, 52, 100} {
+		if c.Valid() {
+			t.Errorf("Valid(%d): got true, expected false", int(c))
+		}
+		if r := c.Rank(); r != 0 {
+			t.Errorf("Rank(%d): got %v, expected 0", int(c), int(r))
+		}
+		if s := c.Suit(); s.Valid() {
+			t.Errorf("Suit(%d): got valid suit %v", int(c), s)
+		}
+		if c.IsAce() || c.IsFace() || c.IsSpade() {
+			t.Errorf("%d: invalid card reported as ace, face, or spade", int(c))
+		}
+		if c.SameRank(c) || c.SameSuit(c) {
+			t.Errorf("%d: invalid card matches itself", int(c))
+		}
+		want := fmt.Sprintf("Card(%d)", int(c))
+		if s := c.ASCII(); s != want {
+			t.Errorf("ASCII(%d): got %q, expected %q", int(c), s, want)
+		}
+		if s := c.Unicode(); s != want {
+			t.Errorf("Unicode(%d): got %q, expected %q", int(c), s, want)
+		}
+	}
+	if s := Rank(14).String(); s != "Rank(14)" {
+		t.Errorf("Rank(14).String(): got %v", s)
+	}
+	if s := Suit(4).Name(); s != "Suit(4)" {
+		t.Errorf("Suit(4).Name(): got %v", s)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range []struct {
+		c, d               Card
+		sameRank, sameSuit bool
+		less               bool
+	}{
+		{New(Two, Clubs), New(Two, Spades), true, false, true},
+		{New(Two, Spades), New(Three, Spades), false, true, true},
+		{New(King, Hearts), New(Ace, Hearts), false, true, false},
+		{BigCassino, BigCassino, true, true, false},
+		{New(Ten, Diamonds), -1, false, false, false},
+	} {
+		if got := test.c.SameRank(test.d); got != test.sameRank {
+			t.Errorf("SameRank(%v, %v): got %v, expected %v", test.c, test.d, got, test.sameRank)
+		}
+		if got := test.c.SameSuit(test.d); got != test.sameSuit {
+			t.Errorf("SameSuit(%v, %v): got %v, expected %v", test.c, test.d, got, test.sameSuit)
+		}
+		if got := test.c.Less(test.d); got != test.less {
+			t.Errorf("Less(%v, %v): got %v, expected %v", test.c, test.d, got, test.less)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package card
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// A Deck is a sequence of cards to be dealt.
+type Deck struct {
+	cards []Card
+}
+
+// NewDeck returns a Deck of the 52 cards in ascending order.
+func NewDeck() *Deck {
+	d := &Deck{cards: make([]Card, 52)}
+	for i := range d.cards {
+		d.cards[i] = Card(i)
+	}
+	return d
+}
+
+// DeckOf returns a Deck that deals cards in order.
+func DeckOf(cards []Card) *Deck {
+	return &Deck{cards: append([]Card(nil), cards...)}
+}
+
+// Shuffle shuffles the cards remaining in the Deck using src. A full Deck in
+// ascending order is put in the order of rand.New(src).Perm(52).
+func (d *Deck) Shuffle(src rand.Source) {
+	old := append([]Card(nil), d.cards...)
+	for i, j := range rand.New(src).Perm(len(old)) {
+		d.cards[i] = old[j]
+	}
+}
+
+// Deal removes the next n cards from the Deck and returns them. Deal panics
+// if fewer than n cards remain.
+func (d *Deck) Deal(n int) []Card {
+	if n < 0 || n > len(d.cards) {
+		panic(fmt.Sprintf("card: cannot deal %v cards from a deck of %v", n, len(d.cards)))
+	}
+	cards := append([]Card(nil), d.cards[:n]...)
+	d.cards = d.cards[n:]
+	return cards
+}
+
+// Remaining returns the number of cards remaining in the Deck.
+func (d *Deck) Remaining() int { return len(d.cards) }
+
+// Cards returns the cards remaining in the Deck in the order they will be
+// dealt.
+func (d *Deck) Cards() []Card { return append([]Card(nil), d.cards...) }
@@ -0,0 +1,56 @@
+package card
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDeck(t *testing.T) {
+	d := NewDeck()
+	if n := d.Remaining(); n != 52 {
+		t.Fatalf("Remaining() = %v, expected 52", n)
+	}
+	d.Shuffle(rand.NewSource(1))
+	var want []Card
+	for _, v := range rand.New(rand.NewSource(1)).Perm(52) {
+		want = append(want, Card(v))
+	}
+	if got := d.Cards(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Shuffle: got %v, expected %v", got, want)
+	}
+
+	if got := d.Deal(4); !reflect.DeepEqual(got, want[:4]) {
+		t.Errorf("Deal(4) = %v, expected %v", got, want[:4])
+	}
+	if n := d.Remaining(); n != 48 {
+		t.Errorf("Remaining() = %v, expected 48", n)
+	}
+	var s Set
+	for d.Remaining() > 0 {
+		for _, c := range d.Deal(4) {
+			s = s.Add(c)
+		}
+	}
+	if s.Union(NewSet(want[:4]...)) != All {
+		t.Errorf("dealt %v, expected all cards", s)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Deal(1) from an empty Deck did not panic")
+		}
+	}()
+	d.Deal(1)
+}
+
+func TestDeckOf(t *testing.T) {
+	cards := []Card{5, 3, 1}
+	d := DeckOf(cards)
+	cards[0] = 0
+	if got, want := d.Deal(2), []Card{5, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Deal(2) = %v, expected %v", got, want)
+	}
+	if got, want := d.Cards(), []Card{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cards() = %v, expected %v", got, want)
+	}
+}
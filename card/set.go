@@ -0,0 +1,92 @@
+package card
+
+import "math/bits"
+
+// A Set is a set of cards, represented as a bitset in which bit c is set if
+// the set contains Card c. The zero Set is empty.
+type Set uint64
+
+const (
+	// All contains all 52 cards.
+	All Set = 1<<52 - 1
+
+	// spades contains the spades.
+	spades Set = 0x8888888888888
+
+	// aces contains the aces.
+	aces Set = 0xf
+)
+
+// NewSet returns a Set containing cards.
+func NewSet(cards ...Card) Set {
+	var s Set
+	for _, c := range cards {
+		s = s.Add(c)
+	}
+	return s
+}
+
+// Add returns s with c added. If c is not valid, Add returns s.
+func (s Set) Add(c Card) Set {
+	if !c.Valid() {
+		return s
+	}
+	return s | 1<<uint(c)
+}
+
+// Remove returns s with c removed. If c is not valid, Remove returns s.
+func (s Set) Remove(c Card) Set {
+	if !c.Valid() {
+		return s
+	}
+	return s &^ (1 << uint(c))
+}
+
+// Contains reports whether s contains c.
+func (s Set) Contains(c Card) bool { return c.Valid() && s&(1<<uint(c)) != 0 }
+
+// Count returns the number of cards in s.
+func (s Set) Count() int { return bits.OnesCount64(uint64(s)) }
+
+// Union returns the cards in either s or t.
+func (s Set) Union(t Set) Set { return s | t }
+
+// Intersect returns the cards in both s and t.
+func (s Set) Intersect(t Set) Set { return s & t }
+
+// Difference returns the cards in s that are not in t.
+func (s Set) Difference(t Set) Set { return s &^ t }
+
+// Spades returns the spades in s.
+func (s Set) Spades() Set { return s & spades }
+
+// Aces returns the aces in s.
+func (s Set) Aces() Set { return s & aces }
+
+// ForEach calls f for each card in s in ascending order.
+func (s Set) ForEach(f func(c Card)) {
+	for s != 0 {
+		f(Card(bits.TrailingZeros64(uint64(s))))
+		s &= s - 1
+	}
+}
+
+// Cards returns the cards in s in ascending order.
+func (s Set) Cards() []Card {
+	cards := make([]Card, 0, s.Count())
+	s.ForEach(func(c Card) { cards = append(cards, c) })
+	return cards
+}
+
+// String returns the cards in s in ascending order, separated by spaces and
+// enclosed in brackets.
+func (s Set) String() string {
+	b := []byte{'['}
+	s.ForEach(func(c Card) {
+		if len(b) > 1 {
+			b = append(b, ' ')
+		}
+		b = append(b, c.String()...)
+	})
+	return string(append(b, ']'))
+}
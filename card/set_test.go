@@ -0,0 +1,82 @@
+package card
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(BigCassino, 0, LittleCassino, 3, 51)
+	if got, want := s.Cards(), []Card{0, 3, LittleCassino, BigCassino, 51}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cards() = %v, expected %v", got, want)
+	}
+	if n := s.Count(); n != 5 {
+		t.Errorf("Count() = %v, expected 5", n)
+	}
+	for c, want := range map[Card]bool{0: true, 1: false, BigCassino: true, 51: true, -1: false, 52: false} {
+		if got := s.Contains(c); got != want {
+			t.Errorf("Contains(%d) = %v, expected %v", int(c), got, want)
+		}
+	}
+	if got, want := s.Spades(), NewSet(3, LittleCassino, 51); got != want {
+		t.Errorf("Spades() = %v, expected %v", got, want)
+	}
+	if got, want := s.Aces(), NewSet(0, 3); got != want {
+		t.Errorf("Aces() = %v, expected %v", got, want)
+	}
+	if got, want := s.Remove(0).Remove(1), NewSet(3, LittleCassino, BigCassino, 51); got != want {
+		t.Errorf("Remove = %v, expected %v", got, want)
+	}
+
+	u := NewSet(0, 1, 2)
+	if got, want := s.Union(u), NewSet(0, 1, 2, 3, LittleCassino, BigCassino, 51); got != want {
+		t.Errorf("Union = %v, expected %v", got, want)
+	}
+	if got, want := s.Intersect(u), NewSet(0); got != want {
+		t.Errorf("Intersect = %v, expected %v", got, want)
+	}
+	if got, want := s.Difference(u), NewSet(3, LittleCassino, BigCassino, 51); got != want {
+		t.Errorf("Difference = %v, expected %v", got, want)
+	}
+	if got, want := s.String(), "[♣A ♠A ♠2 ♦T ♠K]"; got != want {
+		t.Errorf("String() = %q, expected %q", got, want)
+	}
+	if got, want := Set(0).String(), "[]"; got != want {
+		t.Errorf("String() = %q, expected %q", got, want)
+	}
+}
+
+func TestSetInvalid(t *testing.T) {
+	for _, c := range []Card{-1, 52, 63, 64} {
+		if s := NewSet(c); s != 0 {
+			t.Errorf("NewSet(%d) = %#x, expected empty", int(c), uint64(s))
+		}
+		if s := All.Remove(c); s != All {
+			t.Errorf("All.Remove(%d) = %#x, expected All", int(c), uint64(s))
+		}
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	if n := All.Count(); n != 52 {
+		t.Errorf("All.Count() = %v, expected 52", n)
+	}
+	var spades, aces int
+	All.ForEach(func(c Card) {
+		if c.IsSpade() != All.Spades().Contains(c) {
+			t.Errorf("Spades() contains %v: %v", c, All.Spades().Contains(c))
+		}
+		if c.IsAce() != All.Aces().Contains(c) {
+			t.Errorf("Aces() contains %v: %v", c, All.Aces().Contains(c))
+		}
+		if c.IsSpade() {
+			spades++
+		}
+		if c.IsAce() {
+			aces++
+		}
+	})
+	if spades != 13 || aces != 4 {
+		t.Errorf("got %v spades and %v aces", spades, aces)
+	}
+}
@@ -0,0 +1,129 @@
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ranks lists the characters for each rank.
+const ranks = "A23456789TJQK"
+
+// Parse parses a card. It accepts a rank and a suit in either order, as in
+// "♠2", "2♠", "2s", "S2", or "10♦", where the rank is A, 2-10, T, J, Q, or K
+// and the suit is a suit symbol or its initial C, D, H, or S; a Unicode
+// playing card character, as in "🂢"; and the names "Big Cassino" and
+// "Little Cassino". Letters may be in either case.
+func Parse(s string) (Card, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	switch strings.Join(strings.Fields(t), " ") {
+	case "BIG CASSINO":
+		return BigCassino, nil
+	case "LITTLE CASSINO":
+		return LittleCassino, nil
+	}
+	if r, n := utf8.DecodeRuneInString(t); n == len(t) {
+		if c, ok := fromUnicode(r); ok {
+			return c, nil
+		}
+	}
+
+	suit := -1
+	for i, p := range []string{"♣", "♦", "♥", "♠", "C", "D", "H", "S"} {
+		if strings.HasPrefix(t, p) {
+			suit, t = i%4, t[len(p):]
+			break
+		}
+		if strings.HasSuffix(t, p) {
+			suit, t = i%4, t[:len(t)-len(p)]
+			break
+		}
+	}
+	if t == "10" {
+		t = "T"
+	}
+	rank := strings.Index(ranks, t)
+	if suit < 0 || len(t) != 1 || rank < 0 {
+		return 0, fmt.Errorf("invalid card %q", s)
+	}
+	return New(Rank(rank+1), Suit(suit)), nil
+}
+
+// MarshalText encodes a card as its String.
+func (c Card) MarshalText() ([]byte, error) {
+	if !c.Valid() {
+		return nil, fmt.Errorf("invalid card %d", int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a card in any form accepted by Parse.
+func (c *Card) UnmarshalText(text []byte) error {
+	p, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = p
+	return nil
+}
+
+// UnmarshalJSON decodes a card from a JSON string in any form accepted by
+// Parse, or from a JSON number as written before cards were encoded as text.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if !Card(n).Valid() {
+			return fmt.Errorf("invalid card %d", n)
+		}
+		*c = Card(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid card %s", data)
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// ASCII returns a two-character ASCII representation of a card: its rank
+// followed by the initial of its suit, as in "2S" or "TD". A card that is not
+// Valid is written as by String.
+func (c Card) ASCII() string {
+	if !c.Valid() {
+		return c.String()
+	}
+	return c.Rank().String() + suitNames[c.Suit()][:1]
+}
+
+// Unicode returns the character for a card in the Unicode Playing Cards
+// block, as in "🂢" for the two of spades. A card that is not Valid is
+// written as by String.
+func (c Card) Unicode() string {
+	if !c.Valid() {
+		return c.String()
+	}
+	// The block has a row for each suit, in the order spades, hearts,
+	// diamonds, clubs, with knights between jacks and queens.
+	r := int(c.Rank())
+	if c.Rank() > Jack {
+		r++
+	}
+	return string(rune(0x1F0A0 + 0x10*(3-int(c.Suit())) + r))
+}
+
+// fromUnicode returns the card for a character in the Unicode Playing Cards
+// block, and reports whether there is one.
+func fromUnicode(r rune) (Card, bool) {
+	if r < 0x1F0A1 || r > 0x1F0DE {
+		return 0, false
+	}
+	suit, rank := 3-int(r-0x1F0A0)/0x10, int(r-0x1F0A0)%0x10
+	switch {
+	case rank == 0 || rank == 12 || rank > 14:
+		return 0, false
+	case rank > 12:
+		rank--
+	}
+	return New(Rank(rank), Suit(suit)), true
+}
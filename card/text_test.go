@@ -0,0 +1,97 @@
+package card
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for s, c := range map[string]Card{
+		"♠2":             LittleCassino,
+		"2♠":             LittleCassino,
+		"2s":             LittleCassino,
+		"2S":             LittleCassino,
+		"s2":             LittleCassino,
+		" ♠2 ":           LittleCassino,
+		"TD":             BigCassino,
+		"td":             BigCassino,
+		"10♦":            BigCassino,
+		"♦10":            BigCassino,
+		"♦T":             BigCassino,
+		"Big Cassino":    BigCassino,
+		"big  cassino":   BigCassino,
+		"Little Cassino": LittleCassino,
+		"AC":             0,
+		"♠K":             51,
+		"qh":             46,
+		"🂢":              LittleCassino,
+		"🃊":              BigCassino,
+		"🃑":              0,
+		"🂮":              51,
+		"🂭":              47,
+	} {
+		got, err := Parse(s)
+		if err != nil || got != c {
+			t.Errorf("Parse(%q) = %v, %v; expected %v", s, got, err, c)
+		}
+	}
+	for _, s := range []string{"", "♠", "2", "1♠", "11S", "2X", "♠♠2", "ZZ", "🂬", "🂠", "Medium Cassino"} {
+		if c, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %d, expected error", s, int(c))
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	for c := Card(0); c < 52; c++ {
+		for _, s := range []string{c.String(), c.ASCII(), c.Unicode()} {
+			if got, err := Parse(s); err != nil || got != c {
+				t.Errorf("Parse(%q) = %v, %v; expected %v", s, got, err, c)
+			}
+		}
+	}
+	for _, test := range []struct {
+		c              Card
+		ascii, unicode string
+	}{
+		{0, "AC", "🃑"},
+		{LittleCassino, "2S", "🂢"},
+		{BigCassino, "TD", "🃊"},
+		{46, "QH", "🂽"},
+		{51, "KS", "🂮"},
+	} {
+		if s := test.c.ASCII(); s != test.ascii {
+			t.Errorf("ASCII(%v) = %q, expected %q", test.c, s, test.ascii)
+		}
+		if s := test.c.Unicode(); s != test.unicode {
+			t.Errorf("Unicode(%v) = %q, expected %q", test.c, s, test.unicode)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	cards := []Card{0, LittleCassino, BigCassino, 51}
+	b, err := json.Marshal(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `["♣A","♠2","♦T","♠K"]`; got != want {
+		t.Errorf("Marshal(%v) = %s, expected %s", cards, got, want)
+	}
+	for _, s := range []string{`["♣A","♠2","♦T","♠K"]`, `[0,7,37,51]`, `["AC","2s","Big Cassino","K♠"]`} {
+		var got []Card
+		if err := json.Unmarshal([]byte(s), &got); err != nil || !reflect.DeepEqual(got, cards) {
+			t.Errorf("Unmarshal(%s) = %v, %v; expected %v", s, got, err, cards)
+		}
+	}
+	for _, s := range []string{`[52]`, `[-1]`, `["X"]`, `[true]`} {
+		var got []Card
+		if err := json.Unmarshal([]byte(s), &got); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, expected error", s, got)
+		}
+	}
+	if _, err := json.Marshal(Card(-1)); err == nil {
+		t.Error("Marshal(Card(-1)): got nil error")
+	}
+}
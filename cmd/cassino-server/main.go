@@ -0,0 +1,78 @@
+// Command cassino-server hosts games of Cassino between clients that connect
+// over TCP, seating each pair of clients at a table. The protocol is
+// described in package netplay.
+//
+// Usage:
+//
+//	cassino-server [flags]
+//	cassino-server -join host:port [-bot player]
+//
+// With -join, cassino-server instead connects to a server and plays a game as
+// a computer Player, under the rules the server sends.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/internal/cli"
+	"github.com/dkmccandless/cassino/netplay"
+	"github.com/dkmccandless/cassino/strategy"
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("cassino-server: ")
+
+	var (
+		addr     = flag.String("addr", "localhost:7277", "`address` to listen on")
+		seed     = flag.Int64("seed", 0, "seed for the first table's deal, incremented for each table (default random)")
+		retries  = flag.Int("retries", 2, "further actions a client may attempt after an invalid action")
+		timeout  = flag.Duration("timeout", 0, "time limit for each move (default none)")
+		join     = flag.String("join", "", "connect to the server at `address` and play instead of serving")
+		bot      = flag.String("bot", "heuristic", "computer `player` for -join: random, greedy, heuristic, or montecarlo")
+		rollouts = flag.Int("rollouts", 100, "rollouts per decision for montecarlo")
+		rules    game.Rules
+	)
+	cli.RuleFlags(flag.CommandLine, &rules)
+	flag.Parse()
+
+	if *join != "" {
+		// The bot plays by the server's rules; the rule flags are ignored.
+		mc := strategy.MonteCarloOptions{Rollouts: *rollouts}
+		if _, err := strategy.New(*bot, mc); err != nil {
+			log.Fatal(err)
+		}
+		newPlayer := func(in netplay.Init) game.Player {
+			mc.Rules, mc.Players = in.Rules, in.Players
+			p, _ := strategy.New(*bot, mc)
+			return p
+		}
+		conn, err := net.Dial("tcp", *join)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r, err := netplay.JoinFunc(conn, newPlayer)
+		conn.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("score %v (seed %v)", r.Score, r.Seed)
+		return
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("listening on %v", ln.Addr())
+	s := &netplay.Server{
+		Options: game.Options{Seed: *seed, Rules: rules},
+		Retries: *retries,
+		Timeout: *timeout,
+		Logf:    log.Printf,
+	}
+	log.Fatal(s.Serve(ln))
+}
@@ -0,0 +1,91 @@
+// Command cassino-tournament runs a tournament among computer Players for
+// Cassino and reports the standings.
+//
+// Usage:
+//
+//	cassino-tournament [flags] [player ...]
+//
+// The players are named by strategy: random, greedy, heuristic, and
+// montecarlo. A name may be repeated. If no players are named, random,
+// greedy, and heuristic play.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/internal/cli"
+	"github.com/dkmccandless/cassino/strategy"
+	"github.com/dkmccandless/cassino/tournament"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cassino-tournament: ")
+
+	var (
+		format   = flag.String("format", "roundrobin", "pairing `format`: roundrobin or swiss")
+		rounds   = flag.Int("rounds", 0, "number of Swiss rounds (default log2 of the number of players)")
+		deals    = flag.Int("deals", 10, "number of deals per pairing, each played twice")
+		workers  = flag.Int("workers", 0, "number of games played concurrently (default GOMAXPROCS)")
+		seed     = flag.Int64("seed", 0, "seed for the deals (default random)")
+		rollouts = flag.Int("rollouts", 100, "rollouts per decision for montecarlo")
+		budget   = flag.Duration("budget", 0, "time budget per decision for montecarlo")
+		rules    game.Rules
+	)
+	cli.RuleFlags(flag.CommandLine, &rules)
+	flag.Parse()
+
+	opts := tournament.Options{
+		Rounds:  *rounds,
+		Deals:   *deals,
+		Rules:   rules,
+		Workers: *workers,
+		Seed:    *seed,
+	}
+	switch *format {
+	case "roundrobin":
+		opts.Format = tournament.RoundRobin
+	case "swiss":
+		opts.Format = tournament.Swiss
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	names := flag.Args()
+	if len(names) == 0 {
+		names = []string{"random", "greedy", "heuristic"}
+	}
+	mc := strategy.MonteCarloOptions{Rules: rules, Rollouts: *rollouts, Budget: *budget}
+	var entrants []tournament.Entrant
+	for i, name := range names {
+		name := name
+		if _, err := strategy.New(name, mc); err != nil {
+			log.Fatal(err)
+		}
+		New := func() game.Player {
+			p, _ := strategy.New(name, mc)
+			return p
+		}
+		entrants = append(entrants, tournament.Entrant{Name: fmt.Sprintf("%v.%v", i+1, name), New: New})
+	}
+
+	start := time.Now()
+	r, err := tournament.Run(opts, entrants...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v games in %v, seed %v\n\n", len(r.Games), time.Since(start).Round(time.Millisecond), r.Seed)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "player\tgames\twins\tlosses\tdraws\twin rate\tmean diff\t95% CI\telo\t")
+	for _, s := range r.Standings {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%.3f\t%+.2f\t±%.2f\t%.0f\t\n",
+			s.Name, s.Games, s.Wins, s.Losses, s.Draws, s.WinRate, s.MeanDiff, s.CI, s.Elo)
+	}
+	w.Flush()
+}
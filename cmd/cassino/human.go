@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+const help = `Moves:
+  trail ♥7                        play a card to the table
+  capture ♦7, ♣3+♥4 with ♥7       capture ♦7, and ♣3 and ♥4 together
+  build 9: ♣2 onto ♦3+♥4          build 9 from ♣2, ♦3, and ♥4
+  build 8: ♥3 onto [♦2 ♠3]        raise a build of 5 to 8
+  build 7: ♥7 and ♦7              build 7s from ♥7 and ♦7
+  build 10: ♣2 onto ♦8 and ♥10    build 10s from ♣2 and ♦8, and ♥10
+A pile may be named by any of its cards or by its number on the table.
+Cards may be typed in ASCII, as in h7 or 7H. Other commands:
+  moves                           list the legal moves
+  table                           show the table and your hand
+  help                            show this message
+  quit                            leave the game`
+
+// A human is a Player that takes its Actions from a person at a terminal.
+type human struct {
+	in    *bufio.Scanner
+	out   io.Writer
+	rules game.Rules
+	names []string
+
+	// pos is the player's position.
+	pos int
+
+	// hand contains the cards in the player's hand.
+	hand []card.Card
+
+	// quit reports whether the person has left the game.
+	quit bool
+}
+
+func (h *human) Init(pos, dealer int, piles map[int]game.Pile) {
+	h.pos, h.hand = pos, nil
+	fmt.Fprintf(h.out, "%v deals.\n", h.names[dealer])
+}
+
+func (h *human) Hand(hand []card.Card) {
+	h.hand = append(h.hand, hand...)
+	sort.Slice(h.hand, func(i, j int) bool { return h.hand[i] < h.hand[j] })
+	if len(hand) == 1 {
+		fmt.Fprintf(h.out, "You draw %v.\n", hand[0])
+	}
+}
+
+func (h *human) Note(played card.Card, captured []card.Card) {}
+
+func (h *human) NoteTurn(t game.Turn) {
+	if t.Player == h.pos {
+		return
+	}
+	fmt.Fprintf(h.out, "%v: %v\n", h.names[t.Player], game.FormatAction(t.Action, t.Piles))
+	if t.Sweep {
+		fmt.Fprintf(h.out, "%v sweeps the table!\n", h.names[t.Player])
+	}
+}
+
+func (h *human) EndHand(deck int) {
+	if deck != 0 {
+		fmt.Fprintf(h.out, "\nNew round. %v cards left in the deck.\n", deck)
+	}
+}
+
+func (h *human) End(r game.Result) {
+	if len(r.Clear) > 0 {
+		fmt.Fprintf(h.out, "%v takes the cards left on the table: %v\n",
+			h.names[r.LastCapture], formatCards(r.Clear))
+	}
+	fmt.Fprintln(h.out, "\nFinal score:")
+	for i, b := range r.Breakdown {
+		p := b.Points
+		fmt.Fprintf(h.out, "  %-10v %2d  (%v cards, %v spades, scoring %v, %v sweeps)\n",
+			h.names[i], r.Score[i], b.Cards, b.Spades, formatCards(b.Scoring), b.Sweeps)
+		fmt.Fprintf(h.out, "  %-10v     most cards %v, most spades %v, spades %v, big cassino %v, little cassino %v, aces %v, sweeps %v\n",
+			"", p.MostCards, p.MostSpades, p.Spades, p.BigCassino, p.LittleCassino, p.Aces, p.Sweeps)
+	}
+}
+
+func (h *human) Play(piles map[int]game.Pile) game.Action {
+	fmt.Fprintln(h.out)
+	h.show(piles)
+	for {
+		fmt.Fprint(h.out, "> ")
+		if !h.in.Scan() {
+			h.quit = true
+			return game.Action{Card: -1}
+		}
+		line := strings.TrimSpace(h.in.Text())
+		switch strings.ToLower(line) {
+		case "":
+			continue
+		case "quit", "exit":
+			h.quit = true
+			return game.Action{Card: -1}
+		case "help", "?":
+			fmt.Fprintln(h.out, help)
+			continue
+		case "moves":
+			for _, a := range h.rules.LegalActions(h.hand, piles, h.pos) {
+				fmt.Fprintf(h.out, "  %v\n", game.FormatAction(a, piles))
+			}
+			continue
+		case "table":
+			h.show(piles)
+			continue
+		}
+		a, err := game.ParseAction(line, piles)
+		if err == nil {
+			err = h.rules.Validate(game.Position{Hand: h.hand, Piles: piles}, h.pos, a)
+		}
+		if err != nil {
+			fmt.Fprintf(h.out, "%v. Type help for help.\n", err)
+			continue
+		}
+		for i, c := range h.hand {
+			if c == a.Card {
+				h.hand = append(h.hand[:i], h.hand[i+1:]...)
+				break
+			}
+		}
+		return a
+	}
+}
+
+// show displays the table and the player's hand.
+func (h *human) show(piles map[int]game.Pile) {
+	fmt.Fprintln(h.out, "Table:")
+	if len(piles) == 0 {
+		fmt.Fprintln(h.out, "  (empty)")
+	}
+	ids := make([]int, 0, len(piles))
+	for id := range piles {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		fmt.Fprintf(h.out, "  %3d  %v\n", id, h.describe(piles[id]))
+	}
+	fmt.Fprintf(h.out, "Your hand: %v\n", formatCards(h.hand))
+}
+
+// describe returns a description of a pile.
+func (h *human) describe(p game.Pile) string {
+	s := formatCards(p.Cards)
+	if len(p.Cards) < 2 {
+		return s
+	}
+	kind := "build"
+	if p.Compound {
+		kind = "compound build"
+	}
+	return fmt.Sprintf("%-16v %v of %v, controlled by %v", s, kind, p.Value, h.names[p.Controller])
+}
+
+// formatCards returns cards separated by spaces.
+func formatCards(cards []card.Card) string {
+	ss := make([]string, len(cards))
+	for i, c := range cards {
+		ss[i] = c.String()
+	}
+	return strings.Join(ss, " ")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/strategy"
+)
+
+func TestHuman(t *testing.T) {
+	// Offer to trail every card in turn until one is in hand.
+	var in strings.Builder
+	for n := 0; n < 30; n++ {
+		for c := card.Card(0); c < 52; c++ {
+			in.WriteString("trail " + c.String() + "\n")
+		}
+	}
+	var out bytes.Buffer
+	h := &human{
+		in:    bufio.NewScanner(strings.NewReader(in.String())),
+		out:   &out,
+		names: []string{"You", "Computer"},
+	}
+	r, err := game.PlayGame(game.Options{Seed: 1, Dealer: 1}, h, strategy.NewGreedy(game.Rules{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.quit {
+		t.Fatal("ran out of input")
+	}
+	if len(r.Keep[0])+len(r.Keep[1]) != 52 {
+		t.Errorf("captured %v cards", len(r.Keep[0])+len(r.Keep[1]))
+	}
+	for _, s := range []string{"Table:", "Your hand:", "Computer:", "Final score:"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output does not contain %q", s)
+		}
+	}
+}
+
+func TestHumanQuit(t *testing.T) {
+	var out bytes.Buffer
+	h := &human{
+		in:    bufio.NewScanner(strings.NewReader("help\nmoves\nbuild 3: ♠K onto 99\nquit\n")),
+		out:   &out,
+		names: []string{"You", "Computer"},
+	}
+	if _, err := game.PlayGame(game.Options{Seed: 1, Dealer: 1}, h, strategy.NewGreedy(game.Rules{})); err == nil {
+		t.Error("PlayGame: got nil error after quit")
+	}
+	if !h.quit {
+		t.Error("quit not recorded")
+	}
+	if !strings.Contains(out.String(), "Type help for help.") {
+		t.Errorf("invalid move not reported:\n%v", out.String())
+	}
+}
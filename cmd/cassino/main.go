@@ -0,0 +1,70 @@
+// Command cassino plays a game of Cassino against a computer Player in the
+// terminal.
+//
+// Usage:
+//
+//	cassino [flags]
+//
+// Type help during the game for the notation for moves.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/internal/cli"
+	"github.com/dkmccandless/cassino/strategy"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cassino: ")
+
+	var (
+		bot      = flag.String("bot", "heuristic", "computer `player`: random, greedy, heuristic, or montecarlo")
+		seed     = flag.Int64("seed", 0, "seed for the deal (default random)")
+		dealer   = flag.Bool("deal", false, "deal, so that the computer plays first")
+		rollouts = flag.Int("rollouts", 100, "rollouts per decision for montecarlo")
+		rules    game.Rules
+	)
+	cli.RuleFlags(flag.CommandLine, &rules)
+	flag.Parse()
+
+	p, err := strategy.New(*bot, strategy.MonteCarloOptions{Rules: rules, Rollouts: *rollouts})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	h := &human{
+		in:    bufio.NewScanner(os.Stdin),
+		out:   os.Stdout,
+		rules: rules,
+		names: []string{"You", "Computer"},
+	}
+	opts := game.Options{Seed: *seed, Rules: rules, Dealer: 1}
+	if *dealer {
+		opts.Dealer = 0
+	}
+	fmt.Println("Type help for help.")
+	r, err := game.PlayGame(opts, h, p)
+	switch {
+	case h.quit:
+		fmt.Println()
+		return
+	case err != nil:
+		log.Fatal(err)
+	}
+	switch {
+	case r.Score[0] > r.Score[1]:
+		fmt.Println("You win!")
+	case r.Score[0] < r.Score[1]:
+		fmt.Println("The computer wins.")
+	default:
+		fmt.Println("It's a draw.")
+	}
+	fmt.Printf("Seed: %v\n", r.Seed)
+}
@@ -2,205 +2,533 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/dkmccandless/cassino/card"
 )
 
-// A game administers a single complete game.
+// A game administers a single complete game among Players, asking each for
+// its Actions and informing them as the Game advances.
 type game struct {
+	*Game
+
 	// players records the Players in order.
 	players []Player
-
-	// score records each player's score.
-	score []int
-
-	// hand contains the cards in each player's hand.
-	hand []map[card.Card]bool
-
-	// keep contains the cards captured by each player.
-	keep [][]card.Card
-
-	// deck contains the cards not yet dealt.
-	deck []card.Card
-
-	// piles contains the cards on the table.
-	piles map[int]Pile
-
-	// npiles records how many Piles have been added.
-	npiles int
-
-	// lastCapture records who played the most recent capture.
-	lastCapture int
 }
 
 // An Action describes the action a player takes on their turn.
 type Action struct {
 	// Card is the player's hand card.
-	Card card.Card
+	Card card.Card `json:"card"`
 
 	// Add lists any IDs of Piles to be combined with the hand card to create a
 	// build of a higher value. Add may not contain any compound builds.
-	Add []int
+	Add []int `json:"add,omitempty"`
 
 	// Sets lists any IDs of Piles to be captured or built with.
 	// For face cards, each Pile must be in its own set.
 	// For number cards, the sum of the values of the Piles in each set must
 	// equal the sum of the hand card's rank and the values of the Piles in Add.
-	Sets [][]int
+	Sets [][]int `json:"sets,omitempty"`
 
 	// Build reports whether the Action creates or modifies a build.
-	Build bool
+	Build bool `json:"build,omitempty"`
 }
 
 // A Pile contains cards on the table.
 type Pile struct {
 	// Cards lists the cards in the Pile.
 	// A build contains two or more cards.
-	Cards []card.Card
+	Cards []card.Card `json:"cards"`
 
 	// Value is the Pile's numerical value.
 	// Face cards have no value.
-	Value int
+	Value int `json:"value"`
 
 	// Compound records whether the Pile is a compound build.
 	// A compound build has been built from two or more sets.
 	// Its value cannot subsequently change.
-	Compound bool
+	Compound bool `json:"compound,omitempty"`
 
 	// Controller is the player who last played onto the Pile, if it is a build.
-	Controller int
+	Controller int `json:"controller,omitempty"`
 }
 
-// Play plays a game of Cassino and returns the final score.
-func Play(p0, p1 Player) []int {
-	g := &game{
-		players: []Player{p0, p1},
-		score:   []int{0, 0},
-		hand: []map[card.Card]bool{
-			make(map[card.Card]bool, 4),
-			make(map[card.Card]bool, 4),
-		},
-		keep:  make([][]card.Card, 2),
-		piles: make(map[int]Pile),
+// Play plays a game of Cassino among two, three, or four players and returns
+// the final score. Play panics if a Player takes an invalid Action; use
+// PlayGame to handle invalid Actions without panicking.
+func Play(players ...Player) []int {
+	r, err := PlayGame(Options{}, players...)
+	if err != nil {
+		panic(err)
 	}
-	for _, v := range rand.Perm(52) {
-		g.deck = append(g.deck, card.Card(v))
+	return r.Score
+}
+
+// Options configures a game.
+type Options struct {
+	// Policy determines how the game responds to an invalid Action.
+	Policy Policy
+
+	// Retries is the number of further Actions a Player may attempt after an
+	// invalid Action when Policy is Retry.
+	Retries int
+
+	// Deck, if not nil, lists the cards in the order they are dealt.
+	// It must contain each of the 52 cards exactly once.
+	Deck []card.Card
+
+	// Source, if not nil and Deck is nil, is used to shuffle the deck.
+	Source rand.Source
+
+	// Seed, if nonzero and Deck and Source are nil, seeds the Source used to
+	// shuffle the deck. If Deck, Source, and Seed are all unset, a random
+	// Seed is chosen.
+	Seed int64
+
+	// Dealer is the position of the player who deals. Play begins with the
+	// player after the dealer, and the dealer plays last in each round.
+	// By default player 0 deals, so player 1 plays first; for player 0 to
+	// play first, set Dealer to the last position.
+	Dealer int
+
+	// Rules selects the variant of Cassino to play.
+	Rules Rules
+
+	// Partnerships, in a four-player game, seats players 0 and 2 against
+	// players 1 and 3. Partners pool their captures and sweeps for scoring
+	// and share a score.
+	Partnerships bool
+}
+
+// A Policy determines how a game responds to an invalid Action.
+type Policy int
+
+const (
+	// Forfeit ends the game with an *ActionError.
+	Forfeit Policy = iota
+
+	// Retry asks the Player for another Action, up to Options.Retries times,
+	// before forfeiting.
+	Retry
+
+	// Substitute replaces the invalid Action with a valid one.
+	Substitute
+)
+
+// A Rejecter is a Player that can be informed that its Action was invalid.
+// Under the Retry and Substitute policies, Reject is called before the game
+// asks for another Action or substitutes one.
+type Rejecter interface {
+	Reject(a Action, err error)
+}
+
+// An ActionError records an invalid Action and the state of the table when
+// it was taken.
+type ActionError struct {
+	// Player is the position of the Player who took the Action.
+	Player int
+
+	// Action is the invalid Action.
+	Action Action
+
+	// Hand lists the cards in the Player's hand.
+	Hand []card.Card
+
+	// Piles contains the cards on the table.
+	Piles map[int]Pile
+
+	// Err describes why the Action is invalid. Its message begins with the
+	// Action in move notation.
+	Err error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("player %v with hand %v: invalid action %v", e.Player, e.Hand, e.Err)
+}
+
+func (e *ActionError) Unwrap() error { return e.Err }
+
+// A Result describes the outcome of a game.
+type Result struct {
+	// Score records each player's score.
+	Score []int
+
+	// Breakdown itemizes each player's score.
+	Breakdown []ScoreBreakdown
+
+	// Keep lists the cards each player captured, including Clear.
+	Keep [][]card.Card
+
+	// Clear lists the cards left on the table at the end of the game, which
+	// are awarded to LastCapture.
+	Clear []card.Card
+
+	// LastCapture is the player who captured last, or the dealer if no
+	// player captured.
+	LastCapture int
+
+	// Log lists the events of the game in order.
+	Log []Event
+
+	// Seed is the Seed used to shuffle the deck, or 0 if the deck was
+	// supplied by Options.Deck or Options.Source.
+	Seed int64
+
+	// Deck lists the cards in the order they were dealt. Playing another
+	// game with the same Players, Dealer, and Options.Deck set to Deck
+	// replays it.
+	Deck []card.Card
+
+	// Dealer is the position of the player who dealt.
+	Dealer int
+}
+
+// PlayGame plays a game of Cassino among two, three, or four players according
+// to opts and returns the result. If a Player forfeits by taking an invalid
+// Action, PlayGame returns an *ActionError. PlayGame asks each Player for its
+// Actions in turn; to submit Actions as they arrive instead, use NewGame.
+func PlayGame(opts Options, players ...Player) (Result, error) {
+	gm, err := newGame(opts, len(players))
+	if err != nil {
+		return Result{}, err
 	}
-	for _, c := range g.deck[:4] {
-		g.addCardPile(c)
+	g := &game{Game: gm, players: append([]Player{}, players...)}
+	gm.notify = g
+	for i := range g.players {
+		g.players[i].Init(i, g.state.dealer, g.state.copyPiles())
 	}
-	g.deck = g.deck[4:]
+	g.start()
+	for !g.Done() {
+		player := g.ToMove()
+		a, err := g.action(player)
+		if err != nil {
+			return Result{}, err
+		}
+		g.take(a)
+	}
+	r, _ := g.Result()
+	for _, p := range g.players {
+		if ge, ok := p.(GameEnder); ok {
+			ge.End(copyResult(r))
+		}
+	}
+	return r, nil
+}
 
-	for i := range g.players {
-		piles := make(map[int]Pile, len(g.piles))
-		for id, p := range g.piles {
-			piles[id] = copyPile(p)
+// copyResult returns a Result deeply equal to r that does not share memory
+// with r, except for the Events in Log.
+func copyResult(r Result) Result {
+	c := r
+	c.Score = append([]int(nil), r.Score...)
+	c.Breakdown = nil
+	for _, b := range r.Breakdown {
+		b.Scoring = append([]card.Card(nil), b.Scoring...)
+		c.Breakdown = append(c.Breakdown, b)
+	}
+	c.Keep = nil
+	for _, k := range r.Keep {
+		c.Keep = append(c.Keep, append([]card.Card(nil), k...))
+	}
+	c.Clear = append([]card.Card(nil), r.Clear...)
+	c.Log = append([]Event(nil), r.Log...)
+	c.Deck = append([]card.Card(nil), r.Deck...)
+	return c
+}
+
+// validateOptions checks whether opts can configure a game among n players.
+func validateOptions(opts Options, n int) error {
+	if n < 2 || n > 4 {
+		return fmt.Errorf("invalid number of players %v", n)
+	}
+	if opts.Partnerships && n != 4 {
+		return fmt.Errorf("partnerships require four players")
+	}
+	if opts.Dealer < 0 || opts.Dealer >= n {
+		return fmt.Errorf("invalid dealer %v", opts.Dealer)
+	}
+	return nil
+}
+
+// newDeck returns the Deck described by opts and the Seed used to shuffle it,
+// or 0 if the Deck was supplied by opts.Deck or opts.Source.
+func newDeck(opts Options) (*card.Deck, int64, error) {
+	switch {
+	case opts.Deck != nil:
+		if err := validateDeck(opts.Deck); err != nil {
+			return nil, 0, err
 		}
-		g.players[i].Init(i, piles)
+		return card.DeckOf(opts.Deck), 0, nil
+	case opts.Source != nil:
+		return shuffle(opts.Source), 0, nil
 	}
-	for len(g.deck) != 0 {
-		g.playHand()
+	seed := opts.Seed
+	for seed == 0 {
+		seed = rand.Int63()
 	}
-	for id := range g.piles {
-		g.capture(g.lastCapture, id)
+	return shuffle(rand.NewSource(seed)), seed, nil
+}
+
+// shuffle returns a Deck shuffled using src.
+func shuffle(src rand.Source) *card.Deck {
+	d := card.NewDeck()
+	d.Shuffle(src)
+	return d
+}
+
+// validateDeck checks whether deck contains each of the 52 cards exactly once.
+func validateDeck(deck []card.Card) error {
+	if len(deck) != 52 {
+		return fmt.Errorf("invalid deck: %v cards", len(deck))
 	}
+	var seen card.Set
+	for _, c := range deck {
+		if !c.Valid() {
+			return fmt.Errorf("invalid deck: invalid card %d", int(c))
+		}
+		if seen.Contains(c) {
+			return fmt.Errorf("invalid deck: duplicate card %v", c)
+		}
+		seen = seen.Add(c)
+	}
+	return nil
+}
 
-	for i := range g.players {
-		g.score[i] += score(g.keep[i])
+// noteTurn informs the Players of a Turn.
+func (g *game) noteTurn(t Turn) {
+	for j, p := range g.players {
+		if tn, ok := p.(TurnNoter); ok {
+			tn.NoteTurn(copyTurn(t))
+		} else if j != t.Player {
+			p.Note(t.Action.Card, append([]card.Card{}, t.Captured...))
+		}
 	}
-	return g.score
 }
 
-// playHand deals and plays a four-card hand.
-func (g *game) playHand() {
-	for i, p := range g.players {
-		for _, c := range g.deck[:4] {
-			g.hand[i][c] = true
+// noteDeal gives the cards dealt to a player to the Player.
+func (g *game) noteDeal(player int, cards []card.Card) {
+	g.players[player].Hand(append([]card.Card{}, cards...))
+}
+
+// noteEndHand informs the Players that a round has ended.
+func (g *game) noteEndHand() {
+	for _, p := range g.players {
+		if he, ok := p.(HandEnder); ok {
+			he.EndHand(g.state.deck.Remaining())
 		}
-		p.Hand(append([]card.Card{}, g.deck[:4]...))
-		g.deck = g.deck[4:]
 	}
+}
 
-	for len(g.hand[0]) != 0 {
-		for i, p := range g.players {
-			piles := make(map[int]Pile, len(g.piles))
-			for id, p := range g.piles {
-				piles[id] = copyPile(p)
+// noteClear does nothing: Players learn of the final clear from the Result.
+func (g *game) noteClear(player int, cards []card.Card) {}
+
+// team returns the side a player scores for. In a partnership game, partners
+// sit opposite each other.
+func (s *State) team(player int) int {
+	if s.partnerships {
+		return player % 2
+	}
+	return player
+}
+
+// order returns the players' positions in order of play, beginning with the
+// player after the dealer.
+func (s *State) order() []int {
+	order := make([]int, len(s.hand))
+	for k := range order {
+		order[k] = (s.dealer + 1 + k) % len(s.hand)
+	}
+	return order
+}
+
+// action asks a player for an Action and applies the game's Policy until it
+// obtains a valid one.
+func (g *game) action(player int) (Action, error) {
+	p := g.players[player]
+	a := p.Play(g.state.copyPiles())
+	for n := 0; ; n++ {
+		err := g.state.validateAction(player, a)
+		if err == nil {
+			return a, nil
+		}
+		switch {
+		case g.opts.Policy == Retry && n < g.opts.Retries:
+			if r, ok := p.(Rejecter); ok {
+				r.Reject(a, err)
+			}
+			a = p.Play(g.state.copyPiles())
+		case g.opts.Policy == Substitute:
+			if r, ok := p.(Rejecter); ok {
+				r.Reject(a, err)
 			}
-			a := p.Play(piles)
-			if err := g.validateAction(i, a); err != nil {
-				panic(err)
+			return g.state.substitute(player), nil
+		default:
+			return Action{}, &ActionError{
+				Player: player,
+				Action: a,
+				Hand:   g.state.hand[player].Cards(),
+				Piles:  g.state.copyPiles(),
+				Err:    err,
 			}
-			captured := g.do(i, a)
-			g.players[1-i].Note(a.Card, captured)
 		}
 	}
 }
 
-// do performs a valid Action and returns all cards that go to player's keep.
-func (g *game) do(player int, a Action) []card.Card {
+// substitute returns a valid Action for player. If player controls no builds,
+// it trails their lowest card; otherwise it captures the controlled builds of
+// the lowest value with a card of that value.
+func (s *State) substitute(player int) Action {
+	hand := s.hand[player].Cards()
+	value := 0
+	for _, p := range s.piles {
+		if len(p.Cards) > 1 && p.Controller == player && (value == 0 || p.Value < value) {
+			value = p.Value
+		}
+	}
+	if value == 0 {
+		return Action{Card: hand[0]}
+	}
+	a := Action{}
+	for _, c := range hand {
+		if s.rules.HasValue(c, value) {
+			a.Card = c
+			break
+		}
+	}
+	for _, id := range s.pileIDs() {
+		if p := s.piles[id]; len(p.Cards) > 1 && p.Controller == player && p.Value == value {
+			a.Sets = append(a.Sets, []int{id})
+		}
+	}
+	return a
+}
+
+// do performs a valid Action and returns the resulting Turn.
+func (s *State) do(player int, a Action) Turn {
+	t := Turn{Player: player, Action: a, Piles: make(map[int]Pile)}
+	for _, set := range a.Sets {
+		for _, id := range set {
+			t.Piles[id] = copyPile(s.piles[id])
+		}
+	}
+	for _, id := range a.Add {
+		t.Piles[id] = copyPile(s.piles[id])
+	}
+
 	switch {
 	case len(a.Add) == 0 && len(a.Sets) == 0:
 		// Trail
-		delete(g.hand[player], a.Card)
-		g.addCardPile(a.Card)
+		s.hand[player] = s.hand[player].Remove(a.Card)
+		s.addCardPile(a.Card)
+		p := copyPile(s.piles[s.npiles])
+		t.ID, t.Pile = s.npiles, &p
 	case a.isBuild():
-		value := a.Card.Rank()
-		for _, id := range a.Add {
-			value += g.piles[id].Value
-		}
 		p := Pile{
-			Value:      value,
+			Value:      s.rules.actionValue(s.piles, a),
 			Compound:   len(a.Sets) > 0,
 			Controller: player,
 		}
 		for _, set := range a.Sets {
 			for _, id := range set {
-				p.Cards = append(p.Cards, g.piles[id].Cards...)
-				delete(g.piles, id)
+				p.Cards = append(p.Cards, s.piles[id].Cards...)
+				delete(s.piles, id)
 			}
 		}
 		for _, id := range a.Add {
-			p.Cards = append(p.Cards, g.piles[id].Cards...)
-			delete(g.piles, id)
+			p.Cards = append(p.Cards, s.piles[id].Cards...)
+			delete(s.piles, id)
 		}
 		p.Cards = append(p.Cards, a.Card)
-		delete(g.hand[player], a.Card)
-		g.addPile(p)
+		s.hand[player] = s.hand[player].Remove(a.Card)
+		s.addPile(p)
+		p = copyPile(p)
+		t.ID, t.Pile = s.npiles, &p
 	default:
 		// Capture
-		var cards []card.Card
 		for _, set := range a.Sets {
 			for _, id := range set {
-				cards = append(cards, g.piles[id].Cards...)
-				g.capture(player, id)
+				t.Captured = append(t.Captured, s.piles[id].Cards...)
+				s.capture(player, id)
 			}
 		}
-		cards = append(cards, a.Card)
-		g.keep[player] = append(g.keep[player], a.Card)
-		delete(g.hand[player], a.Card)
-		g.lastCapture = player
-		if len(g.piles) == 0 {
+		t.Captured = append(t.Captured, a.Card)
+		s.keep[player] = append(s.keep[player], a.Card)
+		s.hand[player] = s.hand[player].Remove(a.Card)
+		s.lastCapture = player
+		if len(s.piles) == 0 {
 			// Sweep
-			g.score[player]++
+			s.sweeps[player]++
+			t.Sweep = true
 		}
-		return cards
+	}
+	return t
+}
+
+// Errors returned by Validate. Each error an invalid Action produces wraps one
+// of these, so callers can test for them with errors.Is.
+var (
+	ErrNotInHand          = errors.New("card not in hand")
+	ErrTrailWhileBuilding = errors.New("cannot trail while building")
+	ErrUnknownPile        = errors.New("unknown pile")
+	ErrDuplicatePile      = errors.New("duplicate pile")
+	ErrFaceBuild          = errors.New("cannot build with a face card")
+	ErrBadFaceSet         = errors.New("invalid set for face card")
+	ErrAddCompound        = errors.New("cannot add compound builds")
+	ErrAddFace            = errors.New("cannot add face cards")
+	ErrFaceInSet          = errors.New("cannot use face cards in number sets")
+	ErrBadSetSum          = errors.New("invalid set sum")
+	ErrUncapturableBuild  = errors.New("uncapturable build")
+	ErrControlledBuild    = errors.New("no card left to capture controlled build")
+)
+
+// A Position describes the table as seen by a player on their turn.
+type Position struct {
+	// Hand lists the cards in the player's hand.
+	Hand []card.Card
+
+	// Piles contains the cards on the table.
+	Piles map[int]Pile
+}
+
+// Validate checks whether player may take Action a in pos under the basic
+// rules. If not, the error it returns wraps one of the Err values describing
+// why.
+func Validate(pos Position, player int, a Action) error {
+	return Rules{}.Validate(pos, player, a)
+}
+
+// Validate checks whether player may take Action a in pos under r. If not,
+// the error it returns wraps one of the Err values describing why.
+func (r Rules) Validate(pos Position, player int, a Action) error {
+	return r.validate(card.NewSet(pos.Hand...), pos.Piles, player, a)
+}
+
+// validateAction checks whether an Action is valid. The error it returns
+// begins with the Action in move notation.
+func (s *State) validateAction(player int, a Action) error {
+	if err := s.rules.validate(s.hand[player], s.piles, player, a); err != nil {
+		return fmt.Errorf("%v: %w", FormatAction(a, s.piles), err)
 	}
 	return nil
 }
 
-// validateAction checks whether an Action is valid.
-func (g *game) validateAction(player int, a Action) error {
-	if !g.hand[player][a.Card] {
-		return fmt.Errorf("invalid card %v", a.Card)
+// validate checks whether player, holding hand, may take Action a with piles
+// on the table.
+func (r Rules) validate(hand card.Set, piles map[int]Pile, player int, a Action) error {
+	if !hand.Contains(a.Card) {
+		return fmt.Errorf("%w: %v", ErrNotInHand, a.Card)
 	}
 	if len(a.Add) == 0 && len(a.Sets) == 0 {
 		// Trail
-		for _, p := range g.piles {
+		if r.TrailWhileBuilding {
+			return nil
+		}
+		for _, p := range piles {
 			if len(p.Cards) > 1 && p.Controller == player {
-				return fmt.Errorf("cannot trail while building")
+				return ErrTrailWhileBuilding
 			}
 		}
 		return nil
@@ -210,36 +538,36 @@ func (g *game) validateAction(player int, a Action) error {
 	ids := make(map[int]bool)
 	for _, set := range a.Sets {
 		for _, id := range set {
-			if _, ok := g.piles[id]; !ok {
-				return fmt.Errorf("invalid pile %v", id)
+			if _, ok := piles[id]; !ok {
+				return fmt.Errorf("%w %v", ErrUnknownPile, id)
 			}
 			if ids[id] {
-				return fmt.Errorf("duplicate pile %v", id)
+				return fmt.Errorf("%w %v", ErrDuplicatePile, id)
 			}
 			ids[id] = true
 		}
 	}
 	for _, id := range a.Add {
-		if _, ok := g.piles[id]; !ok {
-			return fmt.Errorf("invalid pile %v", id)
+		if _, ok := piles[id]; !ok {
+			return fmt.Errorf("%w %v", ErrUnknownPile, id)
 		}
 		if ids[id] {
-			return fmt.Errorf("duplicate pile %v", id)
+			return fmt.Errorf("%w %v", ErrDuplicatePile, id)
 		}
 		ids[id] = true
 	}
 
 	// Face card sets must have exactly one card of matching rank
-	if a.Card.IsFace() {
+	if r.isFace(a.Card) {
 		if a.isBuild() {
-			return fmt.Errorf("cannot build with a face card")
+			return ErrFaceBuild
 		}
 		for _, set := range a.Sets {
 			if len(set) != 1 {
-				return fmt.Errorf("invalid set %v using %v", set, a.Card)
+				return fmt.Errorf("%w: %v using %v", ErrBadFaceSet, formatSets([][]int{set}, piles), a.Card)
 			}
-			if c := g.piles[set[0]].Cards[0]; c.Rank() != a.Card.Rank() {
-				return fmt.Errorf("invalid capture %v using %v", c, a.Card)
+			if c := piles[set[0]].Cards[0]; !c.SameRank(a.Card) {
+				return fmt.Errorf("%w: %v using %v", ErrBadFaceSet, c, a.Card)
 			}
 		}
 		return nil
@@ -247,53 +575,76 @@ func (g *game) validateAction(player int, a Action) error {
 
 	// Add may only contain single number cards and simple builds
 	for _, id := range a.Add {
-		if g.piles[id].Value == 0 {
-			return fmt.Errorf("invalid added pile %v", g.piles[id])
+		if piles[id].Value == 0 {
+			return fmt.Errorf("%w: %v", ErrAddFace, formatPile(id, piles))
 		}
-		if g.piles[id].Compound {
-			return fmt.Errorf("cannot add compound builds")
+		if piles[id].Compound {
+			return fmt.Errorf("%w: %v", ErrAddCompound, formatPile(id, piles))
 		}
 	}
 
 	// Number card sets must have the correct sum and contain no face cards
-	value := a.Card.Rank()
-	for _, id := range a.Add {
-		value += g.piles[id].Value
-	}
+	value := r.actionValue(piles, a)
 	for _, set := range a.Sets {
 		var sum int
 		for _, id := range set {
-			v := g.piles[id].Value
+			v := piles[id].Value
 			if v == 0 {
-				return fmt.Errorf("invalid pile %v using %v", g.piles[id], a.Card)
+				return fmt.Errorf("%w: %v using %v", ErrFaceInSet, formatPile(id, piles), a.Card)
 			}
 			sum += v
 		}
 		if sum != value {
-			return fmt.Errorf("invalid set %v (sum %v) using %v", set, sum, a.Card)
+			return fmt.Errorf("%w: %v (sum %v) using %v", ErrBadSetSum, formatSets([][]int{set}, piles), sum, a.Card)
 		}
 	}
 
 	if a.isBuild() {
 		// Builds must have a card in hand that can capture
-		if !haveSameRank(g.hand[player], value, a.Card) {
-			return fmt.Errorf("uncapturable build")
+		if !r.haveValue(hand, value, a.Card) {
+			return fmt.Errorf("%w: no card of value %v", ErrUncapturableBuild, value)
 		}
-		// Valid build
-		return nil
 	}
 
-	// Captures must leave a card in hand that can capture any other controlled builds
-	for id, p := range g.piles {
+	// Captures and builds must leave a card in hand that can capture any
+	// other controlled builds
+	for id, p := range piles {
 		if !ids[id] && len(p.Cards) > 1 && p.Controller == player &&
-			!haveSameRank(g.hand[player], p.Value, a.Card) {
-			return fmt.Errorf("no card left to capture controlled build")
+			!r.haveValue(hand, p.Value, a.Card) {
+			return fmt.Errorf("%w: %v", ErrControlledBuild, formatPile(id, piles))
 		}
 	}
-	// Valid capture
+	// Valid capture or build
 	return nil
 }
 
+// actionValue returns the value of a number card Action: the value of the
+// hand card plus the values of the Piles in Add. If the hand card may take
+// more than one value, the value matching the sum of the first set is chosen
+// if there is one, and otherwise the lowest.
+func (r Rules) actionValue(piles map[int]Pile, a Action) int {
+	var add int
+	for _, id := range a.Add {
+		add += piles[id].Value
+	}
+	values := r.Values(a.Card)
+	if len(values) == 0 {
+		return 0
+	}
+	if len(a.Sets) > 0 {
+		var sum int
+		for _, id := range a.Sets[0] {
+			sum += piles[id].Value
+		}
+		for _, v := range values {
+			if v+add == sum {
+				return sum
+			}
+		}
+	}
+	return values[0] + add
+}
+
 // isBuild reports whether an Action is a build.
 // An Action is a build if it has a non-empty Add or its Build flag is set.
 func (a Action) isBuild() bool {
@@ -301,63 +652,139 @@ func (a Action) isBuild() bool {
 }
 
 // addCardPile adds a new Pile containing a single card to the table.
-func (g *game) addCardPile(c card.Card) {
-	var value int
-	if !c.IsFace() {
-		value = c.Rank()
-	}
-	g.addPile(Pile{Cards: []card.Card{c}, Value: value})
+func (s *State) addCardPile(c card.Card) {
+	s.addPile(Pile{Cards: []card.Card{c}, Value: s.rules.value(c)})
 }
 
 // addPile adds a new Pile to the table.
-func (g *game) addPile(p Pile) {
-	g.npiles++
-	g.piles[g.npiles] = p
+func (s *State) addPile(p Pile) {
+	s.npiles++
+	s.piles[s.npiles] = p
 }
 
 // capture moves a Pile into a player's keep.
-func (g *game) capture(player int, id int) {
-	g.keep[player] = append(g.keep[player], g.piles[id].Cards...)
-	delete(g.piles, id)
+func (s *State) capture(player int, id int) {
+	s.keep[player] = append(s.keep[player], s.piles[id].Cards...)
+	delete(s.piles, id)
+}
+
+// A ScoreBreakdown itemizes a player's score.
+type ScoreBreakdown struct {
+	// Cards is the number of cards captured.
+	Cards int `json:"cards"`
+
+	// Spades is the number of spades captured.
+	Spades int `json:"spades"`
+
+	// Scoring lists the captured cards that score individually:
+	// Big Cassino, Little Cassino, and aces.
+	Scoring []card.Card `json:"scoring,omitempty"`
+
+	// Sweeps is the number of sweeps made.
+	Sweeps int `json:"sweeps"`
+
+	// Points records the points scored in each category.
+	Points Points `json:"points"`
+}
+
+// Points records the points scored in each category.
+type Points struct {
+	MostCards     int `json:"mostCards"`
+	MostSpades    int `json:"mostSpades"`
+	Spades        int `json:"spades,omitempty"`
+	BigCassino    int `json:"bigCassino"`
+	LittleCassino int `json:"littleCassino"`
+	Aces          int `json:"aces"`
+	Sweeps        int `json:"sweeps"`
+}
+
+// Total returns the total number of points scored.
+func (b ScoreBreakdown) Total() int {
+	p := b.Points
+	return p.MostCards + p.MostSpades + p.Spades + p.BigCassino + p.LittleCassino + p.Aces + p.Sweeps
 }
 
-// score returns the score of a slice of cards.
-func score(cards []card.Card) int {
+// score returns the score of each side given the cards it captured and its
+// number of sweeps. The points for most cards and most spades are not awarded
+// in case of a tie.
+func (r Rules) score(keeps [][]card.Card, sweeps []int) []ScoreBreakdown {
 	// Most cards: 3
-	// Most spades: 1
+	// Most spades: 1 (Spade Cassino: each spade 1, jack of spades and Little Cassino 2)
 	// Big Cassino: 2
 	// Little Cassino: 1
 	// Each ace: 1
-	var n, spades int
-	if len(cards) > 26 {
-		n += 3
-	}
-	for _, c := range cards {
-		if c.IsSpade() {
-			spades++
+	// Each sweep: 1
+	bs := make([]ScoreBreakdown, len(keeps))
+	for i, cards := range keeps {
+		b := &bs[i]
+		b.Cards, b.Sweeps = len(cards), sweeps[i]
+		if !r.NoSweeps {
+			b.Points.Sweeps = sweeps[i]
 		}
-		switch {
-		case c == card.BigCassino:
-			n += 2
-		case c == card.LittleCassino, c.IsAce():
-			n++
+		for _, c := range cards {
+			if c.IsSpade() {
+				b.Spades++
+				if r.SpadeCassino {
+					b.Points.Spades++
+					if c == card.LittleCassino || c.Rank() == card.Jack {
+						b.Points.Spades++
+					}
+				}
+			}
+			switch {
+			case c == card.BigCassino:
+				b.Points.BigCassino = 2
+			case c == card.LittleCassino:
+				b.Points.LittleCassino = 1
+			case c.IsAce():
+				b.Points.Aces++
+			default:
+				continue
+			}
+			b.Scoring = append(b.Scoring, c)
 		}
 	}
-	if spades >= 7 {
-		n++
+	if i, ok := most(bs, func(b ScoreBreakdown) int { return b.Cards }); ok {
+		bs[i].Points.MostCards = 3
+	}
+	if i, ok := most(bs, func(b ScoreBreakdown) int { return b.Spades }); ok && !r.SpadeCassino {
+		bs[i].Points.MostSpades = 1
 	}
-	return n
+	return bs
 }
 
-// haveSameRank reports whether hand contains a card of the given rank besides
-// the excluded card.
-func haveSameRank(hand map[card.Card]bool, rank int, exclude card.Card) bool {
-	for c := range hand {
-		if c != exclude && c.Rank() == rank {
-			return true
+// most returns the index of the ScoreBreakdown with the greatest count, and
+// reports whether it is unique.
+func most(bs []ScoreBreakdown, count func(ScoreBreakdown) int) (int, bool) {
+	max, unique := 0, false
+	for i := range bs {
+		switch n := count(bs[i]); {
+		case i == 0 || n > count(bs[max]):
+			max, unique = i, true
+		case n == count(bs[max]):
+			unique = false
 		}
 	}
-	return false
+	return max, unique
+}
+
+// pileIDs returns the IDs of the Piles on the table in ascending order.
+func (s *State) pileIDs() []int {
+	ids := make([]int, 0, len(s.piles))
+	for id := range s.piles {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// copyPiles returns a copy of the table that does not share memory with it.
+func (s *State) copyPiles() map[int]Pile {
+	piles := make(map[int]Pile, len(s.piles))
+	for id, p := range s.piles {
+		piles[id] = copyPile(p)
+	}
+	return piles
 }
 
 // copyPile returns a Pile deeply equal to p that does not share memory with p.
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -8,30 +9,30 @@ import (
 )
 
 var actionTests = map[string]struct {
-	g      game
+	g      State
 	player int
 	a      Action
 	isErr  bool
-	want   game
+	want   State
 }{
 	"invalid card": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{1: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(1),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{},
 		},
 		0,
 		Action{Card: 0},
 		true,
-		game{},
+		State{},
 	},
 	"trail with owned build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{39: true, 50: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(39, 50),
 			},
 			piles: map[int]Pile{
 				14: Pile{Cards: []card.Card{2, 34, 38}, Value: 10, Controller: 1},
@@ -40,52 +41,52 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 50},
 		true,
-		game{},
+		State{},
 	},
 	"invalid ID": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{10: Pile{Cards: []card.Card{1}, Value: 1}},
 		},
 		0,
 		Action{Card: 0, Sets: [][]int{{11}}},
 		true,
-		game{},
+		State{},
 	},
 	"duplicate ID in sets": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{10: Pile{Cards: []card.Card{1}, Value: 1}},
 		},
 		0,
 		Action{Card: 0, Sets: [][]int{{10}, {10}}},
 		true,
-		game{},
+		State{},
 	},
 	"duplicate ID in add": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 8: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 8),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{10: Pile{Cards: []card.Card{1}, Value: 1}},
 		},
 		0,
 		Action{Card: 0, Add: []int{10, 10}},
 		true,
-		game{},
+		State{},
 	},
 	"duplicate ID between add and sets": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 24: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 24),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				10: Pile{Cards: []card.Card{1}, Value: 1},
@@ -96,13 +97,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Add: []int{10, 12}, Sets: [][]int{{11, 12}}},
 		true,
-		game{},
+		State{},
 	},
 	"face card with add": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{17: true, 18: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(17, 18),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{40}, Value: 0},
@@ -111,13 +112,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 17, Add: []int{0}},
 		true,
-		game{},
+		State{},
 	},
 	"face build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true, 42: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(40, 42),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{41}, Value: 0},
@@ -126,13 +127,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{0}}, Build: true},
 		true,
-		game{},
+		State{},
 	},
 	"face capture invalid set": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(40),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{41}, Value: 0},
@@ -143,13 +144,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{0, 1, 2}}},
 		true,
-		game{},
+		State{},
 	},
 	"face capture wrong rank": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(40),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{44}, Value: 0},
@@ -158,13 +159,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{0}}},
 		true,
-		game{},
+		State{},
 	},
 	"compound add": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 37: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 37),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{32, 33}, Value: 9, Compound: true},
@@ -173,13 +174,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Add: []int{0}},
 		true,
-		game{},
+		State{},
 	},
 	"face card in add": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{5: true, 18: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(5, 18),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{40}, Value: 0},
@@ -189,13 +190,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 5, Add: []int{0, 1}},
 		true,
-		game{},
+		State{},
 	},
 	"face set": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{17: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(17),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{40}, Value: 0},
@@ -205,13 +206,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 17, Sets: [][]int{{0, 1}}},
 		true,
-		game{},
+		State{},
 	},
 	"wrong set value": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36),
+				card.NewSet(20),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{0}, Value: 1},
@@ -221,13 +222,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 36, Sets: [][]int{{0, 1}}},
 		true,
-		game{},
+		State{},
 	},
 	"build with no hand card": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{8: true, 15: true, 42: true, 45: true},
-				map[card.Card]bool{20: true, 21: true, 22: true, 23: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(8, 15, 42, 45),
+				card.NewSet(20, 21, 22, 23),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{0}, Value: 1},
@@ -237,13 +238,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 8, Sets: [][]int{{0, 1}}, Build: true},
 		true,
-		game{},
+		State{},
 	},
 	"add build with no hand card": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 33: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 33),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				0: Pile{Cards: []card.Card{32}, Value: 9},
@@ -252,13 +253,13 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Add: []int{0}},
 		true,
-		game{},
+		State{},
 	},
 	"uncaptured build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{32: true, 36: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(32, 36),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{4, 24}, Value: 9, Controller: 1},
@@ -271,14 +272,31 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 32, Sets: [][]int{{2, 3, 4}}},
 		true,
-		game{},
+		State{},
+	},
+	"build leaving controlled build": {
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(24, 36),
+			},
+			piles: map[int]Pile{
+				1: Pile{Cards: []card.Card{4, 16}, Value: 7, Controller: 1},
+				2: Pile{Cards: []card.Card{8}, Value: 3},
+			},
+			npiles: 2,
+		},
+		1,
+		Action{Card: 24, Add: []int{2}},
+		true,
+		State{},
 	},
 
 	"trail": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{39: true, 50: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(39, 50),
 			},
 			piles: map[int]Pile{
 				14: Pile{Cards: []card.Card{2, 34, 38}, Value: 10, Controller: 0},
@@ -288,10 +306,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 50},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{39: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(39),
 			},
 			piles: map[int]Pile{
 				14: Pile{Cards: []card.Card{2, 34, 38}, Value: 10, Controller: 0},
@@ -301,10 +319,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"face single": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(40),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{50}, []card.Card{}},
 			piles: map[int]Pile{
@@ -315,10 +333,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{1}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{50, 41, 40}, []card.Card{}},
 			piles: map[int]Pile{
@@ -327,10 +345,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"face multiple": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(40),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{49, 50}, []card.Card{}},
 			piles: map[int]Pile{
@@ -343,10 +361,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{1}, {2}, {3}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{49, 50, 41, 42, 43, 40}, []card.Card{}},
 			piles: map[int]Pile{
@@ -355,10 +373,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number single capture": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -369,10 +387,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Sets: [][]int{{1}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{1, 0}, []card.Card{}},
 			piles: map[int]Pile{
@@ -381,10 +399,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number multiple capture": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -396,10 +414,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Sets: [][]int{{1}, {2}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{1, 2, 0}, []card.Card{}},
 			piles: map[int]Pile{
@@ -408,10 +426,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number sum capture": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -424,10 +442,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 36, Sets: [][]int{{1, 2, 3}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{0, 4, 24, 36}, []card.Card{}},
 			piles: map[int]Pile{
@@ -436,10 +454,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number multiple sums capture": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -453,10 +471,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 36, Sets: [][]int{{1, 4}, {2, 3}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{0, 32, 4, 28, 36}, []card.Card{}},
 			piles: map[int]Pile{
@@ -465,10 +483,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number mixed capture": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -483,10 +501,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 36, Sets: [][]int{{5}, {1, 4}, {2, 3}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{37, 0, 32, 4, 28, 36}, []card.Card{}},
 			piles: map[int]Pile{
@@ -495,10 +513,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number capture build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{32: true, 36: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(32, 36),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -513,10 +531,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 32, Sets: [][]int{{1}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{36: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(36),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{4, 24, 32}},
 			piles: map[int]Pile{
@@ -529,10 +547,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number single build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 2: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 2),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{1}, Value: 1},
@@ -542,10 +560,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Sets: [][]int{{1}}, Build: true},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{2: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(2),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				2: Pile{Cards: []card.Card{1, 0}, Value: 1, Compound: true, Controller: 0},
@@ -554,10 +572,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number multiple build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 2: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 2),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{1}, Value: 1},
@@ -568,10 +586,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Sets: [][]int{{1}, {2}}, Build: true},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{2: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(2),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				3: Pile{Cards: []card.Card{1, 2, 0}, Value: 1, Compound: true, Controller: 0},
@@ -580,10 +598,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number sum build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{36: true, 39: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(36, 39),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{0}, Value: 1},
@@ -595,10 +613,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 36, Sets: [][]int{{1, 2, 3}}, Build: true},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{39: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(39),
 			},
 			piles: map[int]Pile{
 				4: Pile{Cards: []card.Card{0, 4, 24, 36}, Value: 10, Compound: true, Controller: 1},
@@ -607,10 +625,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number multiple sums build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true, 39: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36, 39),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{0}, Value: 1},
@@ -623,10 +641,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 36, Sets: [][]int{{1, 4}, {2, 3}}, Build: true},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{39: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(39),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				5: Pile{Cards: []card.Card{0, 32, 4, 28, 36}, Value: 10, Compound: true, Controller: 0},
@@ -635,10 +653,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number mixed build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{36: true, 39: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(36, 39),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{0}, Value: 1},
@@ -652,10 +670,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 36, Sets: [][]int{{5}, {1, 4}, {2, 3}}, Build: true},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{39: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(39),
 			},
 			piles: map[int]Pile{
 				6: Pile{Cards: []card.Card{37, 0, 32, 4, 28, 36}, Value: 10, Compound: true, Controller: 1},
@@ -664,10 +682,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number add build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{0: true, 36: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(0, 36),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{32}, Value: 9},
@@ -677,10 +695,10 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 0, Add: []int{1}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{36: true},
-				map[card.Card]bool{20: true, 21: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(36),
+				card.NewSet(20, 21),
 			},
 			piles: map[int]Pile{
 				2: Pile{Cards: []card.Card{32, 0}, Value: 10, Compound: false, Controller: 0},
@@ -689,10 +707,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"number add sets build": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{0: true, 36: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(0, 36),
 			},
 			piles: map[int]Pile{
 				1: Pile{Cards: []card.Card{4}, Value: 2},
@@ -704,10 +722,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 0, Add: []int{3}, Sets: [][]int{{1, 2}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{36: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(36),
 			},
 			piles: map[int]Pile{
 				4: Pile{Cards: []card.Card{4, 28, 32, 0}, Value: 10, Compound: true, Controller: 1},
@@ -716,10 +734,10 @@ var actionTests = map[string]struct {
 		},
 	},
 	"uncaptured build with hand card": {
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{32: true, 33: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(32, 33),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{}},
 			piles: map[int]Pile{
@@ -734,10 +752,10 @@ var actionTests = map[string]struct {
 		1,
 		Action{Card: 32, Sets: [][]int{{2, 3, 4}}},
 		false,
-		game{
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{20: true},
-				map[card.Card]bool{33: true},
+		State{
+			hand: []card.Set{
+				card.NewSet(20),
+				card.NewSet(33),
 			},
 			keep: [][]card.Card{[]card.Card{}, []card.Card{0, 8, 16, 32}},
 			piles: map[int]Pile{
@@ -748,11 +766,11 @@ var actionTests = map[string]struct {
 		},
 	},
 	"sweep": {
-		game{
-			score: []int{0, 0},
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{40: true},
-				map[card.Card]bool{20: true},
+		State{
+			sweeps: []int{0, 0},
+			hand: []card.Set{
+				card.NewSet(40),
+				card.NewSet(20),
 			},
 			keep: [][]card.Card{[]card.Card{50}, []card.Card{}},
 			piles: map[int]Pile{
@@ -762,11 +780,11 @@ var actionTests = map[string]struct {
 		0,
 		Action{Card: 40, Sets: [][]int{{1}}},
 		false,
-		game{
-			score: []int{1, 0},
-			hand: []map[card.Card]bool{
-				map[card.Card]bool{},
-				map[card.Card]bool{20: true},
+		State{
+			sweeps: []int{1, 0},
+			hand: []card.Set{
+				card.Set(0),
+				card.NewSet(20),
 			},
 			keep:  [][]card.Card{[]card.Card{50, 41, 40}, []card.Card{}},
 			piles: map[int]Pile{},
@@ -788,6 +806,39 @@ func TestValidateAction(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	errs := map[string]error{
+		"invalid card":                      ErrNotInHand,
+		"trail with owned build":            ErrTrailWhileBuilding,
+		"invalid ID":                        ErrUnknownPile,
+		"duplicate ID in sets":              ErrDuplicatePile,
+		"duplicate ID in add":               ErrDuplicatePile,
+		"duplicate ID between add and sets": ErrDuplicatePile,
+		"face card with add":                ErrAddFace,
+		"face build":                        ErrFaceBuild,
+		"face capture invalid set":          ErrBadFaceSet,
+		"face capture wrong rank":           ErrBadFaceSet,
+		"compound add":                      ErrAddCompound,
+		"face card in add":                  ErrAddFace,
+		"face set":                          ErrFaceInSet,
+		"wrong set value":                   ErrBadSetSum,
+		"build with no hand card":           ErrUncapturableBuild,
+		"add build with no hand card":       ErrUncapturableBuild,
+		"uncaptured build":                  ErrControlledBuild,
+		"build leaving controlled build":    ErrControlledBuild,
+	}
+	for name, test := range actionTests {
+		pos := Position{
+			Hand:  test.g.hand[test.player].Cards(),
+			Piles: test.g.piles,
+		}
+		err := Validate(pos, test.player, test.a)
+		if want := errs[name]; !errors.Is(err, want) || (want == nil) != (err == nil) {
+			t.Errorf("Validate(%q): got %v, expected %v", name, err, want)
+		}
+	}
+}
+
 func TestDo(t *testing.T) {
 	for name, test := range actionTests {
 		if test.isErr {
@@ -821,17 +872,17 @@ func TestIsBuild(t *testing.T) {
 
 func TestAddCardPile(t *testing.T) {
 	for name, test := range map[string]struct {
-		g    *game
+		g    *State
 		c    card.Card
-		want *game
+		want *State
 	}{
 		"empty": {
-			&game{
+			&State{
 				piles:  map[int]Pile{},
 				npiles: 9,
 			},
 			11,
-			&game{
+			&State{
 				piles: map[int]Pile{
 					10: Pile{Cards: []card.Card{11}, Value: 3},
 				},
@@ -839,7 +890,7 @@ func TestAddCardPile(t *testing.T) {
 			},
 		},
 		"non-empty": {
-			&game{
+			&State{
 				piles: map[int]Pile{
 					6:  Pile{Cards: []card.Card{50}, Value: 0},
 					9:  Pile{Cards: []card.Card{16}, Value: 5},
@@ -849,7 +900,7 @@ func TestAddCardPile(t *testing.T) {
 				npiles: 15,
 			},
 			45,
-			&game{
+			&State{
 				piles: map[int]Pile{
 					6:  Pile{Cards: []card.Card{50}, Value: 0},
 					9:  Pile{Cards: []card.Card{16}, Value: 5},
@@ -871,17 +922,17 @@ func TestAddCardPile(t *testing.T) {
 
 func TestAddPile(t *testing.T) {
 	for name, test := range map[string]struct {
-		g    *game
+		g    *State
 		p    Pile
-		want *game
+		want *State
 	}{
 		"single": {
-			&game{
+			&State{
 				piles:  map[int]Pile{},
 				npiles: 9,
 			},
 			Pile{Cards: []card.Card{11}, Value: 3},
-			&game{
+			&State{
 				piles: map[int]Pile{
 					10: Pile{Cards: []card.Card{11}, Value: 3},
 				},
@@ -889,12 +940,12 @@ func TestAddPile(t *testing.T) {
 			},
 		},
 		"empty": {
-			&game{
+			&State{
 				piles:  map[int]Pile{},
 				npiles: 9,
 			},
 			Pile{Cards: []card.Card{0, 32, 36}, Value: 10},
-			&game{
+			&State{
 				piles: map[int]Pile{
 					10: Pile{Cards: []card.Card{0, 32, 36}, Value: 10},
 				},
@@ -902,7 +953,7 @@ func TestAddPile(t *testing.T) {
 			},
 		},
 		"non-empty": {
-			&game{
+			&State{
 				piles: map[int]Pile{
 					6:  Pile{Cards: []card.Card{50}, Value: 0},
 					9:  Pile{Cards: []card.Card{16}, Value: 5},
@@ -912,7 +963,7 @@ func TestAddPile(t *testing.T) {
 				npiles: 15,
 			},
 			Pile{Cards: []card.Card{19, 15, 3, 31, 32}, Value: 9},
-			&game{
+			&State{
 				piles: map[int]Pile{
 					6:  Pile{Cards: []card.Card{50}, Value: 0},
 					9:  Pile{Cards: []card.Card{16}, Value: 5},
@@ -934,13 +985,13 @@ func TestAddPile(t *testing.T) {
 
 func TestCapture(t *testing.T) {
 	for name, test := range map[string]struct {
-		g      *game
+		g      *State
 		player int
 		id     int
-		want   *game
+		want   *State
 	}{
 		"first": {
-			&game{
+			&State{
 				keep: [][]card.Card{{}, {}},
 				piles: map[int]Pile{
 					0: {Cards: []card.Card{7}, Value: 2},
@@ -950,7 +1001,7 @@ func TestCapture(t *testing.T) {
 			},
 			0,
 			1,
-			&game{
+			&State{
 				keep: [][]card.Card{{16}, {}},
 				piles: map[int]Pile{
 					0: {Cards: []card.Card{7}, Value: 2},
@@ -959,7 +1010,7 @@ func TestCapture(t *testing.T) {
 			},
 		},
 		"sweep": {
-			&game{
+			&State{
 				keep: [][]card.Card{
 					{31, 30, 43, 40, 8, 11},
 					{7, 5, 0, 2, 50, 48},
@@ -968,7 +1019,7 @@ func TestCapture(t *testing.T) {
 			},
 			1,
 			15,
-			&game{
+			&State{
 				keep: [][]card.Card{
 					{31, 30, 43, 40, 8, 11},
 					{7, 5, 0, 2, 50, 48, 25},
@@ -1017,34 +1068,234 @@ func TestScore(t *testing.T) {
 			39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
 		}, 11},
 	} {
-		if n := score(test.cards); n != test.n {
+		// The opponent captures the rest of the deck.
+		keeps := [][]card.Card{test.cards, rest(test.cards)}
+		if n := (Rules{}).score(keeps, []int{0, 0})[0].Total(); n != test.n {
 			t.Errorf("score(%v): got %v, expected %v", test.cards, n, test.n)
 		}
 	}
 }
 
-func TestHaveSameRank(t *testing.T) {
+func TestScoreBreakdown(t *testing.T) {
+	cards := []card.Card{
+		0, 3, 7, 11, 15, 19, 23, 27, 37, 40,
+	}
+	want := ScoreBreakdown{
+		Cards:   10,
+		Spades:  7,
+		Scoring: []card.Card{0, 3, 7, 37},
+		Sweeps:  2,
+		Points: Points{
+			MostSpades:    1,
+			BigCassino:    2,
+			LittleCassino: 1,
+			Aces:          2,
+			Sweeps:        2,
+		},
+	}
+	b := Rules{}.score([][]card.Card{cards, rest(cards)}, []int{2, 0})[0]
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("score(%v, 2): got %+v, expected %+v", cards, b, want)
+	}
+	if n := b.Total(); n != 8 {
+		t.Errorf("Total(%+v): got %v, expected 8", b, n)
+	}
+}
+
+func TestScoreTies(t *testing.T) {
+	for name, test := range map[string]struct {
+		keeps [][]card.Card
+		want  []int
+	}{
+		"two-way tie": {
+			[][]card.Card{{4, 5, 6}, {8, 9, 10}},
+			[]int{0, 0},
+		},
+		"three-way": {
+			[][]card.Card{{4, 5}, {8, 9, 10}, {12, 13}},
+			[]int{0, 3, 0},
+		},
+		"three-way tie for most": {
+			[][]card.Card{{4, 5}, {8, 9, 10}, {12, 13, 14}},
+			[]int{0, 0, 0},
+		},
+		"most spades": {
+			[][]card.Card{{4, 11, 15}, {8, 9, 10}, {12, 13, 14}},
+			[]int{1, 0, 0},
+		},
+	} {
+		bs := Rules{}.score(test.keeps, make([]int, len(test.keeps)))
+		var got []int
+		for _, b := range bs {
+			got = append(got, b.Total())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("score(%q): got %v, expected %v", name, got, test.want)
+		}
+	}
+}
+
+// rest returns the cards not in cards.
+func rest(cards []card.Card) []card.Card {
+	in := make(map[card.Card]bool)
+	for _, c := range cards {
+		in[c] = true
+	}
+	var r []card.Card
+	for c := card.Card(0); c < 52; c++ {
+		if !in[c] {
+			r = append(r, c)
+		}
+	}
+	return r
+}
+
+func TestHaveValue(t *testing.T) {
 	for _, test := range []struct {
-		hand    map[card.Card]bool
-		rank    int
+		r       Rules
+		hand    card.Set
+		value   int
 		exclude card.Card
 		want    bool
 	}{
-		{map[card.Card]bool{}, 9, 32, false},
-		{map[card.Card]bool{32: true}, 9, 32, false},
-		{map[card.Card]bool{32: true, 33: true, 34: true}, 9, 32, true},
-		{map[card.Card]bool{32: true, 33: true, 37: true}, 9, 32, true},
-		{map[card.Card]bool{32: true, 36: true, 37: true}, 9, 32, false},
+		{Rules{}, card.Set(0), 9, 32, false},
+		{Rules{}, card.NewSet(32), 9, 32, false},
+		{Rules{}, card.NewSet(32, 33, 34), 9, 32, true},
+		{Rules{}, card.NewSet(32, 33, 37), 9, 32, true},
+		{Rules{}, card.NewSet(32, 36, 37), 9, 32, false},
+		{Rules{}, card.NewSet(32, 40), 11, 32, false},
+		{Rules{Royal: true}, card.NewSet(32, 40), 11, 32, true},
+		{Rules{}, card.NewSet(32, 0), 14, 32, false},
+		{Rules{Royal: true}, card.NewSet(32, 0), 14, 32, true},
+		{Rules{Royal: true}, card.NewSet(32, 0), 1, 32, true},
 	} {
-		got := haveSameRank(test.hand, test.rank, test.exclude)
+		got := test.r.haveValue(test.hand, test.value, test.exclude)
 		if got != test.want {
-			t.Errorf("haveSameRank(%v, %v, %v): got %v, expected %v",
-				test.hand, test.rank, test.exclude, got, test.want,
+			t.Errorf("haveValue(%+v, %v, %v, %v): got %v, expected %v",
+				test.r, test.hand, test.value, test.exclude, got, test.want,
 			)
 		}
 	}
 }
 
+func TestRulesScore(t *testing.T) {
+	keeps := [][]card.Card{
+		{3, 7, 43, 11, 37, 0, 1},
+		{15, 19, 2},
+	}
+	for name, test := range map[string]struct {
+		r    Rules
+		want []int
+	}{
+		"basic":         {Rules{}, []int{3 + 1 + 2 + 1 + 3 + 2, 1 + 1}},
+		"spade cassino": {Rules{SpadeCassino: true}, []int{3 + 6 + 2 + 1 + 3 + 2, 2 + 1 + 1}},
+		"no sweeps":     {Rules{NoSweeps: true}, []int{3 + 1 + 2 + 1 + 3, 1}},
+	} {
+		var got []int
+		for _, b := range test.r.score(keeps, []int{2, 1}) {
+			got = append(got, b.Total())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("score(%q): got %v, expected %v", name, got, test.want)
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	for name, test := range map[string]struct {
+		r     Rules
+		pos   Position
+		a     Action
+		isErr bool
+	}{
+		"royal face build": {
+			Rules{Royal: true},
+			Position{
+				Hand:  []card.Card{4, 48},
+				Piles: map[int]Pile{1: {Cards: []card.Card{40}, Value: 11}},
+			},
+			Action{Card: 4, Add: []int{1}},
+			false,
+		},
+		"basic face build": {
+			Rules{},
+			Position{
+				Hand:  []card.Card{4, 48},
+				Piles: map[int]Pile{1: {Cards: []card.Card{40}}},
+			},
+			Action{Card: 4, Add: []int{1}},
+			true,
+		},
+		"royal ace 14": {
+			Rules{Royal: true},
+			Position{
+				Hand:  []card.Card{0},
+				Piles: map[int]Pile{1: {Cards: []card.Card{48}, Value: 13}, 2: {Cards: []card.Card{1}, Value: 1}},
+			},
+			Action{Card: 0, Sets: [][]int{{1, 2}}},
+			false,
+		},
+		"royal ace 1": {
+			Rules{Royal: true},
+			Position{
+				Hand:  []card.Card{0},
+				Piles: map[int]Pile{2: {Cards: []card.Card{1}, Value: 1}},
+			},
+			Action{Card: 0, Sets: [][]int{{2}}},
+			false,
+		},
+		"royal ace mixed": {
+			Rules{Royal: true},
+			Position{
+				Hand:  []card.Card{0},
+				Piles: map[int]Pile{1: {Cards: []card.Card{48}, Value: 13}, 2: {Cards: []card.Card{1}, Value: 1}, 3: {Cards: []card.Card{2}, Value: 1}},
+			},
+			Action{Card: 0, Sets: [][]int{{1, 2}, {3}}},
+			true,
+		},
+		"trail while building": {
+			Rules{TrailWhileBuilding: true},
+			Position{
+				Hand:  []card.Card{20, 36},
+				Piles: map[int]Pile{1: {Cards: []card.Card{0, 32}, Value: 10}},
+			},
+			Action{Card: 20},
+			false,
+		},
+	} {
+		err := test.r.Validate(test.pos, 0, test.a)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("Validate(%q): got %v, expected error %v", name, err, test.isErr)
+		}
+	}
+}
+
+func TestPlayGameDraw(t *testing.T) {
+	e := &ender{}
+	r, err := PlayGame(Options{Seed: 4, Rules: Rules{Draw: true}}, e, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame: %v", err)
+	}
+	var hands, turns int
+	for _, ev := range r.Log {
+		switch ev.Type {
+		case EventHand:
+			hands++
+		case EventTurn:
+			turns++
+		}
+	}
+	if hands != 2+40 || turns != 48 {
+		t.Errorf("PlayGame: got %v hands and %v turns, expected 42 and 48", hands, turns)
+	}
+	if !reflect.DeepEqual(e.decks, []int{0}) {
+		t.Errorf("PlayGame: got EndHand %v, expected [0]", e.decks)
+	}
+	if _, err := Replay(r.Log); err != nil {
+		t.Errorf("Replay: %v", err)
+	}
+}
+
 func TestCopyPile(t *testing.T) {
 	for _, p := range []Pile{
 		Pile{Cards: []card.Card{51}},
@@ -1060,3 +1311,275 @@ func TestCopyPile(t *testing.T) {
 		}
 	}
 }
+
+// trailer is a Player that trails each card in its hand in turn.
+type trailer struct {
+	hand []card.Card
+}
+
+func (t *trailer) Init(pos, dealer int, piles map[int]Pile) {}
+func (t *trailer) Hand(hand []card.Card)                    { t.hand = append(t.hand, hand...) }
+func (t *trailer) Note(card.Card, []card.Card)              {}
+func (t *trailer) Play(piles map[int]Pile) Action {
+	c := t.hand[0]
+	t.hand = t.hand[1:]
+	return Action{Card: c}
+}
+
+// fumbler is a trailer that attempts an invalid Action on its first turn.
+type fumbler struct {
+	trailer
+	fumbled  bool
+	rejected int
+}
+
+func (f *fumbler) Play(piles map[int]Pile) Action {
+	if !f.fumbled {
+		f.fumbled = true
+		return Action{Card: -1}
+	}
+	return f.trailer.Play(piles)
+}
+
+func (f *fumbler) Reject(a Action, err error) { f.rejected++ }
+
+func TestPlayGamePolicy(t *testing.T) {
+	for name, test := range map[string]struct {
+		opts     Options
+		isErr    bool
+		rejected int
+	}{
+		"forfeit":        {Options{}, true, 0},
+		"retry":          {Options{Policy: Retry, Retries: 1}, false, 1},
+		"retry exceeded": {Options{Policy: Retry}, true, 0},
+		"substitute":     {Options{Policy: Substitute}, false, 1},
+	} {
+		f := &fumbler{}
+		_, err := PlayGame(test.opts, f, &trailer{})
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("PlayGame(%q): got error %v, expected error %v", name, err, test.isErr)
+		}
+		var ae *ActionError
+		if err != nil && (!errors.As(err, &ae) || ae.Player != 0 || ae.Action.Card != -1) {
+			t.Errorf("PlayGame(%q): got %v, expected *ActionError for player 0", name, err)
+		}
+		// A substituted Action may leave the fumbler trying to play a card
+		// it no longer holds, so substitution may occur more than once.
+		if f.rejected < test.rejected || test.opts.Policy != Substitute && f.rejected != test.rejected {
+			t.Errorf("PlayGame(%q): got %v rejections, expected %v", name, f.rejected, test.rejected)
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	for name, test := range map[string]struct {
+		g      State
+		player int
+		want   Action
+	}{
+		"trail": {
+			State{
+				hand: []card.Set{
+					card.NewSet(20, 8),
+					card.NewSet(21),
+				},
+				piles: map[int]Pile{
+					1: Pile{Cards: []card.Card{4, 24}, Value: 9, Controller: 1},
+				},
+			},
+			0,
+			Action{Card: 8},
+		},
+		"controlled builds": {
+			State{
+				hand: []card.Set{
+					card.NewSet(20),
+					card.NewSet(32, 36, 12),
+				},
+				piles: map[int]Pile{
+					1: Pile{Cards: []card.Card{0, 28}, Value: 9, Controller: 1},
+					2: Pile{Cards: []card.Card{4, 24}, Value: 9, Controller: 1},
+					3: Pile{Cards: []card.Card{33, 1}, Value: 10, Controller: 1},
+					4: Pile{Cards: []card.Card{8, 16}, Value: 8, Controller: 0},
+				},
+			},
+			1,
+			Action{Card: 32, Sets: [][]int{{1}, {2}}},
+		},
+	} {
+		a := test.g.substitute(test.player)
+		if !reflect.DeepEqual(a, test.want) {
+			t.Errorf("substitute(%q): got %+v, expected %+v", name, a, test.want)
+		}
+		if err := test.g.validateAction(test.player, a); err != nil {
+			t.Errorf("substitute(%q): got invalid action: %v", name, err)
+		}
+	}
+}
+
+func TestPlayGameDeck(t *testing.T) {
+	r1, err := PlayGame(Options{Seed: 42}, &trailer{}, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame(seed): %v", err)
+	}
+	if r1.Seed != 42 {
+		t.Errorf("PlayGame(seed): got seed %v, expected 42", r1.Seed)
+	}
+	r2, err := PlayGame(Options{Seed: 42}, &trailer{}, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame(seed): %v", err)
+	}
+	if !reflect.DeepEqual(r1, r2) {
+		t.Errorf("PlayGame(seed): got %+v and %+v from the same seed", r1, r2)
+	}
+	r3, err := PlayGame(Options{Deck: r1.Deck}, &trailer{}, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame(deck): %v", err)
+	}
+	if r3.Seed != 0 || !reflect.DeepEqual(r3.Deck, r1.Deck) || !reflect.DeepEqual(r3.Score, r1.Score) {
+		t.Errorf("PlayGame(deck): got %+v, expected %+v", r3, r1)
+	}
+	r4, err := PlayGame(Options{}, &trailer{}, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame(): %v", err)
+	}
+	if r4.Seed == 0 {
+		t.Errorf("PlayGame(): got seed 0, expected random seed")
+	}
+
+	for name, deck := range map[string][]card.Card{
+		"short":     r1.Deck[1:],
+		"duplicate": append(append([]card.Card{}, r1.Deck[1:]...), r1.Deck[1]),
+		"invalid":   append(append([]card.Card{}, r1.Deck[1:]...), 52),
+	} {
+		if _, err := PlayGame(Options{Deck: deck}, &trailer{}, &trailer{}); err == nil {
+			t.Errorf("PlayGame(%q): got nil, expected error", name)
+		}
+	}
+}
+
+// recorder is a trailer that records the order in which it plays.
+type recorder struct {
+	trailer
+	pos, dealer int
+	turns       *[]int
+}
+
+func (r *recorder) Init(pos, dealer int, piles map[int]Pile) {
+	r.pos, r.dealer = pos, dealer
+}
+
+func (r *recorder) Play(piles map[int]Pile) Action {
+	*r.turns = append(*r.turns, r.pos)
+	return r.trailer.Play(piles)
+}
+
+func TestPlayGameDealer(t *testing.T) {
+	for _, dealer := range []int{0, 1} {
+		var turns []int
+		p0, p1 := &recorder{turns: &turns}, &recorder{turns: &turns}
+		r, err := PlayGame(Options{Seed: 1, Dealer: dealer}, p0, p1)
+		if err != nil {
+			t.Fatalf("PlayGame(dealer %v): %v", dealer, err)
+		}
+		if r.Dealer != dealer || p0.dealer != dealer || p1.dealer != dealer {
+			t.Errorf("PlayGame(dealer %v): got dealer %v, players told %v and %v",
+				dealer, r.Dealer, p0.dealer, p1.dealer,
+			)
+		}
+		for i, pos := range turns {
+			if want := (dealer + 1 + i) % 2; pos != want {
+				t.Fatalf("PlayGame(dealer %v): turn %v played by %v, expected %v", dealer, i, pos, want)
+			}
+		}
+		// No one captures, so the dealer takes the table.
+		if want := r.Score[dealer]; want < 3 {
+			t.Errorf("PlayGame(dealer %v): got score %v, expected dealer to take the table", dealer, r.Score)
+		}
+	}
+	if _, err := PlayGame(Options{Dealer: 2}, &trailer{}, &trailer{}); err == nil {
+		t.Errorf("PlayGame(dealer 2): got nil, expected error")
+	}
+}
+
+// ender is a trailer that records the end of each hand and game.
+type ender struct {
+	trailer
+	decks  []int
+	result *Result
+}
+
+func (e *ender) EndHand(deck int) { e.decks = append(e.decks, deck) }
+func (e *ender) End(r Result)     { e.result = &r }
+
+func TestPlayGameEnd(t *testing.T) {
+	e := &ender{}
+	r, err := PlayGame(Options{Seed: 9}, e, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame: %v", err)
+	}
+	if want := []int{40, 32, 24, 16, 8, 0}; !reflect.DeepEqual(e.decks, want) {
+		t.Errorf("PlayGame: got EndHand %v, expected %v", e.decks, want)
+	}
+	if e.result == nil || !reflect.DeepEqual(*e.result, r) {
+		t.Fatalf("PlayGame: got End %+v, expected %+v", e.result, r)
+	}
+	// Trailers never capture, so the dealer takes the whole deck.
+	if len(r.Clear) != 52 || r.LastCapture != 0 || len(r.Keep[0]) != 52 || len(r.Keep[1]) != 0 {
+		t.Errorf("PlayGame: got Clear %v, LastCapture %v, Keep %v", r.Clear, r.LastCapture, r.Keep)
+	}
+	e.result.Keep[0][0]++
+	if reflect.DeepEqual(*e.result, r) {
+		t.Errorf("PlayGame: End result shares memory with the returned result")
+	}
+}
+
+func TestPlayGamePlayers(t *testing.T) {
+	for name, test := range map[string]struct {
+		n            int
+		partnerships bool
+		isErr        bool
+	}{
+		"one":               {1, false, true},
+		"two":               {2, false, false},
+		"three":             {3, false, false},
+		"four":              {4, false, false},
+		"five":              {5, false, true},
+		"partnerships":      {4, true, false},
+		"three partnership": {3, true, true},
+	} {
+		var turns []int
+		players := make([]Player, test.n)
+		for i := range players {
+			players[i] = &recorder{turns: &turns}
+		}
+		r, err := PlayGame(Options{Seed: 2, Partnerships: test.partnerships}, players...)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("PlayGame(%q): got %v, expected error %v", name, err, test.isErr)
+		}
+		if err != nil {
+			continue
+		}
+		if len(turns) != 48 {
+			t.Errorf("PlayGame(%q): got %v turns, expected 48", name, len(turns))
+		}
+		for i, pos := range turns {
+			if want := (1 + i) % test.n; pos != want {
+				t.Fatalf("PlayGame(%q): turn %v played by %v, expected %v", name, i, pos, want)
+			}
+		}
+		var cards int
+		for _, k := range r.Keep {
+			cards += len(k)
+		}
+		if cards != 52 || len(r.Score) != test.n {
+			t.Errorf("PlayGame(%q): got %v cards kept and score %v", name, cards, r.Score)
+		}
+		if test.partnerships && (r.Score[0] != r.Score[2] || r.Score[1] != r.Score[3]) {
+			t.Errorf("PlayGame(%q): got partner scores %v", name, r.Score)
+		}
+		if rr, err := Replay(r.Log); err != nil || !reflect.DeepEqual(rr.Score, r.Score) {
+			t.Errorf("Replay(%q): got %v, %v", name, rr.Score, err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// LegalActions returns every valid Action that player can take with the cards
+// in hand under the basic rules. Each Action is listed once in canonical form:
+// the IDs in Add and in each set are in ascending order, Sets is in
+// lexicographic order, and Build is set if and only if the Action creates or
+// modifies a build.
+func LegalActions(hand []card.Card, piles map[int]Pile, player int) []Action {
+	return Rules{}.LegalActions(hand, piles, player)
+}
+
+// LegalActions returns every valid Action that player can take with the cards
+// in hand under r, in the canonical form described by the LegalActions
+// function.
+func (r Rules) LegalActions(hand []card.Card, piles map[int]Pile, player int) []Action {
+	h := card.NewSet(hand...)
+	cards := h.Cards()
+
+	ids := make([]int, 0, len(piles))
+	for id := range piles {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var building bool
+	for _, p := range piles {
+		if len(p.Cards) > 1 && p.Controller == player {
+			building = true
+		}
+	}
+
+	var actions []Action
+	for _, c := range cards {
+		if !building || r.TrailWhileBuilding {
+			actions = append(actions, Action{Card: c})
+		}
+		if r.isFace(c) {
+			actions = append(actions, faceCaptures(c, ids, piles)...)
+			continue
+		}
+
+		// Captures and builds without Add
+		for _, v := range r.Values(c) {
+			for _, sets := range setCollections(v, ids, piles, nil) {
+				if r.canCapture(h, piles, player, c, sets) {
+					actions = append(actions, Action{Card: c, Sets: sets})
+				}
+				if r.haveValue(h, v, c) && r.canCapture(h, piles, player, c, sets) {
+					actions = append(actions, Action{Card: c, Sets: sets, Build: true})
+				}
+			}
+		}
+
+		// Builds with Add
+		var addable []int
+		for _, id := range ids {
+			if p := piles[id]; p.Value != 0 && !p.Compound {
+				addable = append(addable, id)
+			}
+		}
+		for _, v := range r.Values(c) {
+			for _, add := range subsets(addable, piles, func(sum int) bool {
+				return v+sum <= r.maxValue()
+			}) {
+				value := v
+				for _, id := range add {
+					value += piles[id].Value
+				}
+				if !r.haveValue(h, value, c) {
+					continue
+				}
+				if r.canCapture(h, piles, player, c, [][]int{add}) {
+					actions = append(actions, Action{Card: c, Add: add, Build: true})
+				}
+				for _, sets := range setCollections(value, ids, piles, add) {
+					if r.canCapture(h, piles, player, c, append([][]int{add}, sets...)) {
+						actions = append(actions, Action{Card: c, Add: add, Sets: sets, Build: true})
+					}
+				}
+			}
+		}
+	}
+	return actions
+}
+
+// faceCaptures returns the captures available to face card c.
+func faceCaptures(c card.Card, ids []int, piles map[int]Pile) []Action {
+	var match []int
+	for _, id := range ids {
+		if p := piles[id]; p.Value == 0 && p.Cards[0].SameRank(c) {
+			match = append(match, id)
+		}
+	}
+	var actions []Action
+	for _, sub := range subsets(match, piles, nil) {
+		a := Action{Card: c}
+		for _, id := range sub {
+			a.Sets = append(a.Sets, []int{id})
+		}
+		actions = append(actions, a)
+	}
+	return actions
+}
+
+// canCapture reports whether capturing or building with the Piles in sets
+// using c leaves player a card in hand that can capture each of their other
+// builds.
+func (r Rules) canCapture(hand card.Set, piles map[int]Pile, player int, c card.Card, sets [][]int) bool {
+	captured := make(map[int]bool)
+	for _, set := range sets {
+		for _, id := range set {
+			captured[id] = true
+		}
+	}
+	for id, p := range piles {
+		if !captured[id] && len(p.Cards) > 1 && p.Controller == player &&
+			!r.haveValue(hand, p.Value, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// setCollections returns every non-empty collection of disjoint sets of the
+// number Piles in ids, excluding those in exclude, such that the values of the
+// Piles in each set sum to value.
+func setCollections(value int, ids []int, piles map[int]Pile, exclude []int) [][][]int {
+	ex := make(map[int]bool, len(exclude))
+	for _, id := range exclude {
+		ex[id] = true
+	}
+	var cand []int
+	for _, id := range ids {
+		if !ex[id] && piles[id].Value != 0 && piles[id].Value <= value {
+			cand = append(cand, id)
+		}
+	}
+	var sets [][]int
+	for _, sub := range subsets(cand, piles, func(sum int) bool { return sum <= value }) {
+		var sum int
+		for _, id := range sub {
+			sum += piles[id].Value
+		}
+		if sum == value {
+			sets = append(sets, sub)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return lessIDs(sets[i], sets[j]) })
+
+	var colls [][][]int
+	var walk func(start int, used map[int]bool, coll [][]int)
+	walk = func(start int, used map[int]bool, coll [][]int) {
+		for i := start; i < len(sets); i++ {
+			if overlaps(sets[i], used) {
+				continue
+			}
+			next := append(append([][]int{}, coll...), sets[i])
+			colls = append(colls, next)
+			for _, id := range sets[i] {
+				used[id] = true
+			}
+			walk(i+1, used, next)
+			for _, id := range sets[i] {
+				delete(used, id)
+			}
+		}
+	}
+	walk(0, make(map[int]bool), nil)
+	return colls
+}
+
+// subsets returns every non-empty subset of ids, in ascending order, for which
+// ok reports true for the sum of the values of its Piles. Since Pile values
+// are non-negative, subsets are not extended once ok reports false.
+// A nil ok accepts every subset.
+func subsets(ids []int, piles map[int]Pile, ok func(sum int) bool) [][]int {
+	var subs [][]int
+	var walk func(start, sum int, sub []int)
+	walk = func(start, sum int, sub []int) {
+		for i := start; i < len(ids); i++ {
+			s := sum + piles[ids[i]].Value
+			if ok != nil && !ok(s) {
+				continue
+			}
+			next := append(append([]int{}, sub...), ids[i])
+			subs = append(subs, next)
+			walk(i+1, s, next)
+		}
+	}
+	walk(0, 0, nil)
+	return subs
+}
+
+// overlaps reports whether any ID in ids is in used.
+func overlaps(ids []int, used map[int]bool) bool {
+	for _, id := range ids {
+		if used[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// lessIDs reports whether a precedes b in lexicographic order.
+func lessIDs(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
@@ -0,0 +1,200 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+func TestLegalActions(t *testing.T) {
+	for name, test := range map[string]struct {
+		hand   []card.Card
+		piles  map[int]Pile
+		player int
+		want   []Action
+	}{
+		"empty table": {
+			[]card.Card{40, 4},
+			map[int]Pile{},
+			0,
+			[]Action{{Card: 4}, {Card: 40}},
+		},
+		"face": {
+			[]card.Card{40},
+			map[int]Pile{
+				1: {Cards: []card.Card{41}},
+				2: {Cards: []card.Card{44}},
+				3: {Cards: []card.Card{42}},
+			},
+			0,
+			[]Action{
+				{Card: 40},
+				{Card: 40, Sets: [][]int{{1}}},
+				{Card: 40, Sets: [][]int{{1}, {3}}},
+				{Card: 40, Sets: [][]int{{3}}},
+			},
+		},
+		"controlled build": {
+			[]card.Card{32, 0},
+			map[int]Pile{
+				1: {Cards: []card.Card{4, 24}, Value: 9, Controller: 1},
+				2: {Cards: []card.Card{16}, Value: 5},
+				3: {Cards: []card.Card{8}, Value: 3},
+			},
+			1,
+			[]Action{
+				{Card: 0, Add: []int{2, 3}, Build: true},
+				{Card: 0, Add: []int{2, 3}, Sets: [][]int{{1}}, Build: true},
+				{Card: 32, Sets: [][]int{{1}}},
+			},
+		},
+		"build": {
+			[]card.Card{4, 20},
+			map[int]Pile{
+				1: {Cards: []card.Card{12}, Value: 4},
+				2: {Cards: []card.Card{16}, Value: 5},
+			},
+			0,
+			[]Action{
+				{Card: 4},
+				{Card: 4, Add: []int{1}, Build: true},
+				{Card: 20},
+			},
+		},
+	} {
+		if got := LegalActions(test.hand, test.piles, test.player); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("LegalActions(%q): got %+v, expected %+v", name, got, test.want)
+		}
+	}
+}
+
+// TestLegalActionsExhaustive checks LegalActions against every Action
+// accepted by validateAction on random small tables.
+func TestLegalActionsExhaustive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 600; n++ {
+		g := randomTable(rng, Rules{Royal: n%2 == 1, TrailWhileBuilding: n%3 == 2})
+		hand := g.hand[0].Cards()
+		got := g.rules.LegalActions(hand, g.piles, 0)
+		seen := make(map[string]bool)
+		for _, a := range got {
+			if err := g.validateAction(0, a); err != nil {
+				t.Fatalf("LegalActions(%v, %v): invalid %+v: %v", hand, g.piles, a, err)
+			}
+			if k := fmt.Sprint(a); seen[k] {
+				t.Fatalf("LegalActions(%v, %v): duplicate %+v", hand, g.piles, a)
+			} else {
+				seen[k] = true
+			}
+		}
+		want := bruteForceActions(g, 0)
+		if len(got) != len(want) {
+			t.Fatalf("LegalActions(%v, %v): got %v actions, expected %v", hand, g.piles, len(got), len(want))
+		}
+		for k := range want {
+			if !seen[k] {
+				t.Fatalf("LegalActions(%v, %v): missing %v", hand, g.piles, k)
+			}
+		}
+	}
+}
+
+// randomTable returns a State with a random hand for player 0 and up to four
+// Piles on the table, some of which may be builds controlled by either player.
+func randomTable(rng *rand.Rand, r Rules) State {
+	deck := rng.Perm(52)
+	g := State{
+		rules: r,
+		hand:  make([]card.Set, 2),
+		piles: make(map[int]Pile),
+	}
+	for _, v := range deck[:1+rng.Intn(4)] {
+		g.hand[0] = g.hand[0].Add(card.Card(v))
+	}
+	deck = deck[4:]
+	n := rng.Intn(5)
+	for id := 1; id <= n; id++ {
+		c := card.Card(deck[0])
+		deck = deck[1:]
+		p := Pile{Cards: []card.Card{c}, Value: r.value(c)}
+		if p.Value != 0 && rng.Intn(3) == 0 {
+			d := card.Card(deck[0])
+			deck = deck[1:]
+			if v := r.value(d); v != 0 && p.Value+v <= r.maxValue() {
+				p.Cards = append(p.Cards, d)
+				p.Value += v
+				p.Compound = rng.Intn(2) == 0
+				p.Controller = rng.Intn(2)
+			}
+		}
+		g.piles[id] = p
+	}
+	return g
+}
+
+// bruteForceActions returns the canonical form of every Action accepted by
+// validateAction, keyed by its formatted representation.
+func bruteForceActions(g State, player int) map[string]bool {
+	var ids []int
+	for id := range g.piles {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	actions := make(map[string]bool)
+	for _, c := range g.hand[player].Cards() {
+		// Assign each Pile to Add (-1), no set (0), or one of up to len(ids) sets.
+		assign := make([]int, len(ids))
+		var walk func(i int)
+		walk = func(i int) {
+			if i < len(ids) {
+				for v := -1; v <= len(ids); v++ {
+					assign[i] = v
+					walk(i + 1)
+				}
+				return
+			}
+			var add []int
+			sets := make([][]int, len(ids))
+			for j, v := range assign {
+				switch {
+				case v == -1:
+					add = append(add, ids[j])
+				case v > 0:
+					sets[v-1] = append(sets[v-1], ids[j])
+				}
+			}
+			var nonempty [][]int
+			for j, set := range sets {
+				if len(set) == 0 {
+					// Require sets to be numbered consecutively.
+					for _, rest := range sets[j:] {
+						if len(rest) != 0 {
+							return
+						}
+					}
+					break
+				}
+				nonempty = append(nonempty, set)
+			}
+			for _, build := range []bool{false, true} {
+				a := Action{Card: c, Add: add, Sets: nonempty, Build: build}
+				if g.validateAction(player, a) != nil {
+					continue
+				}
+				if len(a.Add) == 0 && len(a.Sets) == 0 {
+					a.Build = false
+				} else {
+					a.Build = a.isBuild()
+				}
+				sort.Slice(a.Sets, func(i, j int) bool { return lessIDs(a.Sets[i], a.Sets[j]) })
+				actions[fmt.Sprint(a)] = true
+			}
+		}
+		walk(0)
+	}
+	return actions
+}
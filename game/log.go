@@ -0,0 +1,221 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// A Turn describes an Action and its effect on the table.
+type Turn struct {
+	// Player is the player who took the Action.
+	Player int `json:"player"`
+
+	// Action is the Action taken.
+	Action Action `json:"action"`
+
+	// Piles contains the Piles the Action removed from the table, by ID.
+	Piles map[int]Pile `json:"piles,omitempty"`
+
+	// ID is the ID of the Pile the Action added to the table, or 0 if it
+	// added none.
+	ID int `json:"id,omitempty"`
+
+	// Pile is the Pile the Action added to the table, or nil if it added
+	// none.
+	Pile *Pile `json:"pile,omitempty"`
+
+	// Captured lists the cards captured, including the hand card.
+	Captured []card.Card `json:"captured,omitempty"`
+
+	// Sweep reports whether the Action captured every card on the table.
+	Sweep bool `json:"sweep,omitempty"`
+}
+
+// copyTurn returns a Turn deeply equal to t that does not share memory with t.
+func copyTurn(t Turn) Turn {
+	c := t
+	c.Action = Action{
+		Card:  t.Action.Card,
+		Add:   append([]int(nil), t.Action.Add...),
+		Build: t.Action.Build,
+	}
+	for _, set := range t.Action.Sets {
+		c.Action.Sets = append(c.Action.Sets, append([]int{}, set...))
+	}
+	if t.Piles != nil {
+		c.Piles = make(map[int]Pile, len(t.Piles))
+		for id, p := range t.Piles {
+			c.Piles[id] = copyPile(p)
+		}
+	}
+	if t.Pile != nil {
+		p := copyPile(*t.Pile)
+		c.Pile = &p
+	}
+	c.Captured = append([]card.Card(nil), t.Captured...)
+	return c
+}
+
+// An EventType identifies the kind of an Event.
+type EventType string
+
+// Events are recorded in the following order. A game begins with EventDeal,
+// and each round begins with an EventHand for each player. Each turn records
+// an EventTurn, followed by an EventCapture if the Action was a capture and an
+// EventSweep if it was a sweep. At the end of the game, an EventClear records
+// the cards left on the table, if any, and an EventScore records the score.
+const (
+	// EventDeal records the deck in Cards, the dealer in Player, the number
+	// of players in Players, whether they play in Partnerships, the Rules,
+	// and the cards dealt to the table in Piles.
+	EventDeal EventType = "deal"
+
+	// EventHand records the cards dealt to Player in Cards.
+	EventHand EventType = "hand"
+
+	// EventTurn records a Player's Turn.
+	EventTurn EventType = "turn"
+
+	// EventCapture records the cards Player captured in Cards.
+	EventCapture EventType = "capture"
+
+	// EventSweep records a sweep by Player.
+	EventSweep EventType = "sweep"
+
+	// EventClear records the cards left on the table at the end of the game
+	// in Cards, which are awarded to Player.
+	EventClear EventType = "clear"
+
+	// EventScore records each player's score in Score.
+	EventScore EventType = "score"
+)
+
+// An Event records something that happened during a game.
+type Event struct {
+	Type         EventType        `json:"type"`
+	Player       int              `json:"player"`
+	Players      int              `json:"players,omitempty"`
+	Partnerships bool             `json:"partnerships,omitempty"`
+	Rules        *Rules           `json:"rules,omitempty"`
+	Cards        []card.Card      `json:"cards,omitempty"`
+	Piles        map[int]Pile     `json:"piles,omitempty"`
+	Turn         *Turn            `json:"turn,omitempty"`
+	Score        []ScoreBreakdown `json:"score,omitempty"`
+}
+
+// emit records an Event.
+func (g *Game) emit(e Event) {
+	g.log = append(g.log, e)
+}
+
+// WriteLog writes log to w in JSON Lines format, one Event per line.
+func WriteLog(w io.Writer, log []Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range log {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadLog reads a log written by WriteLog.
+func ReadLog(r io.Reader) ([]Event, error) {
+	var log []Event
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for n := 1; s.Scan(); n++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %v: %w", n, err)
+		}
+		log = append(log, e)
+	}
+	return log, s.Err()
+}
+
+// ErrReplay is returned by Replay when a log is inconsistent with the game it
+// describes.
+var ErrReplay = errors.New("replay mismatch")
+
+// Replay plays the game recorded in log and verifies that it produces the
+// same events. It returns the result of the replayed game.
+func Replay(log []Event) (Result, error) {
+	if len(log) == 0 || log[0].Type != EventDeal {
+		return Result{}, fmt.Errorf("%w: log does not begin with %v", ErrReplay, EventDeal)
+	}
+	deal := log[0]
+	if deal.Players < 2 || deal.Players > 4 {
+		return Result{}, fmt.Errorf("%w: %v players", ErrReplay, deal.Players)
+	}
+	replayers := make([]*replayer, deal.Players)
+	players := make([]Player, deal.Players)
+	for i := range players {
+		replayers[i] = &replayer{}
+		players[i] = replayers[i]
+	}
+	for _, e := range log {
+		if e.Type == EventTurn && e.Turn != nil && e.Player >= 0 && e.Player < len(players) {
+			replayers[e.Player].actions = append(replayers[e.Player].actions, e.Turn.Action)
+		}
+	}
+
+	opts := Options{
+		Deck:         deal.Cards,
+		Dealer:       deal.Player,
+		Partnerships: deal.Partnerships,
+	}
+	if deal.Rules != nil {
+		opts.Rules = *deal.Rules
+	}
+	r, err := PlayGame(opts, players...)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(r.Log) != len(log) {
+		return r, fmt.Errorf("%w: got %v events, expected %v", ErrReplay, len(r.Log), len(log))
+	}
+	for i := range log {
+		// Compare encodings so that nil and empty values are equivalent.
+		got, err := json.Marshal(r.Log[i])
+		if err != nil {
+			return r, err
+		}
+		want, err := json.Marshal(log[i])
+		if err != nil {
+			return r, err
+		}
+		if !bytes.Equal(got, want) {
+			return r, fmt.Errorf("%w: event %v: got %s, expected %s", ErrReplay, i, got, want)
+		}
+	}
+	return r, nil
+}
+
+// A replayer is a Player that takes the Actions recorded in a log.
+type replayer struct {
+	actions []Action
+}
+
+func (r *replayer) Init(pos, dealer int, piles map[int]Pile)    {}
+func (r *replayer) Hand(hand []card.Card)                       {}
+func (r *replayer) Note(played card.Card, captured []card.Card) {}
+
+func (r *replayer) Play(piles map[int]Pile) Action {
+	if len(r.actions) == 0 {
+		// An invalid Action ends the replay with an *ActionError.
+		return Action{Card: -1}
+	}
+	a := r.actions[0]
+	r.actions = r.actions[1:]
+	return a
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+func TestLog(t *testing.T) {
+	r, err := PlayGame(Options{Seed: 3, Dealer: 1}, &recorder{turns: new([]int)}, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame: %v", err)
+	}
+	log := r.Log
+	if len(log) == 0 || log[0].Type != EventDeal || log[len(log)-1].Type != EventScore {
+		t.Fatalf("PlayGame: got log %+v", log)
+	}
+	if !reflect.DeepEqual(log[0].Cards, r.Deck) || log[0].Player != 1 || len(log[0].Piles) != 4 {
+		t.Errorf("PlayGame: got deal event %+v", log[0])
+	}
+	var turns int
+	for _, e := range log {
+		if e.Type == EventTurn {
+			turns++
+		}
+	}
+	if turns != 48 {
+		t.Errorf("PlayGame: got %v turns, expected 48", turns)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLog(&buf, log); err != nil {
+		t.Fatalf("WriteLog: %v", err)
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != len(log) {
+		t.Errorf("WriteLog: got %v lines, expected %v", n, len(log))
+	}
+	read, err := ReadLog(&buf)
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	rr, err := Replay(read)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !reflect.DeepEqual(rr.Score, r.Score) {
+		t.Errorf("Replay: got score %v, expected %v", rr.Score, r.Score)
+	}
+
+	// Altering an Action makes the replay diverge.
+	for i, e := range read {
+		if e.Type == EventTurn {
+			read[i].Turn.Action.Card = read[i+1].Turn.Action.Card
+			break
+		}
+	}
+	if _, err := Replay(read); err == nil {
+		t.Errorf("Replay(altered): got nil, expected error")
+	}
+
+	// Altering a recorded outcome fails verification.
+	read, _ = ReadLog(bytes.NewReader(mustLog(t, log)))
+	read[len(read)-1].Score[0].Cards++
+	if _, err := Replay(read); !errors.Is(err, ErrReplay) {
+		t.Errorf("Replay(altered score): got %v, expected %v", err, ErrReplay)
+	}
+}
+
+func TestLogCards(t *testing.T) {
+	// Cards are written as text, and logs that record them as numbers
+	// can still be read.
+	var buf bytes.Buffer
+	log := []Event{{Type: EventHand, Player: 1, Cards: []card.Card{0, card.BigCassino}}}
+	if err := WriteLog(&buf, log); err != nil {
+		t.Fatalf("WriteLog: %v", err)
+	}
+	if got, want := buf.String(), `{"type":"hand","player":1,"cards":["♣A","♦T"]}`+"\n"; got != want {
+		t.Errorf("WriteLog: got %s, expected %s", got, want)
+	}
+	for _, s := range []string{
+		`{"type":"hand","player":1,"cards":[0,37]}`,
+		`{"type":"hand","player":1,"cards":["AC","10D"]}`,
+	} {
+		read, err := ReadLog(bytes.NewReader([]byte(s)))
+		if err != nil || !reflect.DeepEqual(read, log) {
+			t.Errorf("ReadLog(%s) = %+v, %v; expected %+v", s, read, err, log)
+		}
+	}
+}
+
+func mustLog(t *testing.T, log []Event) []byte {
+	var buf bytes.Buffer
+	if err := WriteLog(&buf, log); err != nil {
+		t.Fatalf("WriteLog: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// turnNoter is a trailer that records the Turns it is informed of.
+type turnNoter struct {
+	trailer
+	turns []Turn
+	notes int
+}
+
+func (tn *turnNoter) NoteTurn(t Turn)                      { tn.turns = append(tn.turns, t) }
+func (tn *turnNoter) Note(played card.Card, c []card.Card) { tn.notes++ }
+
+func TestNoteTurn(t *testing.T) {
+	tn := &turnNoter{}
+	r, err := PlayGame(Options{Seed: 5}, tn, &trailer{})
+	if err != nil {
+		t.Fatalf("PlayGame: %v", err)
+	}
+	if tn.notes != 0 {
+		t.Errorf("PlayGame: Note called %v times on a TurnNoter", tn.notes)
+	}
+	var want []Turn
+	for _, e := range r.Log {
+		if e.Type == EventTurn {
+			want = append(want, *e.Turn)
+		}
+	}
+	if !reflect.DeepEqual(tn.turns, want) {
+		t.Errorf("PlayGame: got turns %+v, expected %+v", tn.turns, want)
+	}
+	for _, turn := range tn.turns {
+		if turn.ID == 0 || turn.Pile == nil || !reflect.DeepEqual(turn.Pile.Cards, []card.Card{turn.Action.Card}) {
+			t.Errorf("PlayGame: got trail %+v", turn)
+		}
+	}
+}
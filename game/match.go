@@ -0,0 +1,110 @@
+package game
+
+import "math/rand"
+
+// MatchOptions configures a match.
+type MatchOptions struct {
+	// Target is the score that ends the match. If zero, it is 21.
+	Target int
+
+	// Tie determines how a match ends when the players are tied at or above
+	// Target.
+	Tie TieRule
+
+	// Game configures each game of the match. Its Dealer deals the first
+	// game, and the deal alternates thereafter. Its Deck, Source, and Seed
+	// are ignored; each game is shuffled with a Seed drawn from the match's
+	// Seed.
+	Game Options
+
+	// Seed, if nonzero, seeds the sequence of games. If Seed is zero, a random
+	// Seed is chosen.
+	Seed int64
+}
+
+// A TieRule determines how a match ends when the players are tied at or above
+// the target score.
+type TieRule int
+
+const (
+	// PlayOn plays further games until one player leads.
+	PlayOn TieRule = iota
+
+	// TieDraw ends the match without a winner.
+	TieDraw
+)
+
+// A MatchResult describes the outcome of a match.
+type MatchResult struct {
+	// Games records the result of each game in order.
+	Games []Result
+
+	// Score records each player's cumulative score.
+	Score []int
+
+	// Winner is the winning player, or -1 if the match was drawn.
+	// In a partnership match, Winner is the winning partnership: 0 for
+	// players 0 and 2, or 1 for players 1 and 3.
+	Winner int
+
+	// Seed is the Seed used to shuffle the games.
+	Seed int64
+}
+
+// PlayMatch plays games of Cassino among two, three, or four players until a
+// player reaches the target score, rotating the dealer, and returns the
+// result. The Players are reused from game to game; each game begins with a
+// call to Init. If a game ends with an error, PlayMatch returns the games
+// completed so far along with the error.
+func PlayMatch(opts MatchOptions, players ...Player) (MatchResult, error) {
+	if err := validateOptions(opts.Game, len(players)); err != nil {
+		return MatchResult{Winner: -1}, err
+	}
+	target := opts.Target
+	if target == 0 {
+		target = 21
+	}
+	mr := MatchResult{Score: make([]int, len(players)), Winner: -1, Seed: opts.Seed}
+	for mr.Seed == 0 {
+		mr.Seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(mr.Seed))
+
+	for n := 0; ; n++ {
+		gopts := opts.Game
+		gopts.Deck, gopts.Source, gopts.Seed = nil, nil, 0
+		for gopts.Seed == 0 {
+			gopts.Seed = rng.Int63()
+		}
+		gopts.Dealer = (opts.Game.Dealer + n) % len(players)
+
+		r, err := PlayGame(gopts, players...)
+		if err != nil {
+			return mr, err
+		}
+		for i, s := range r.Score {
+			mr.Score[i] += s
+		}
+		mr.Games = append(mr.Games, r)
+
+		// Partners share a score, so the lowest-numbered leader identifies
+		// the leading partnership.
+		leader, tied := 0, false
+		for i, s := range mr.Score {
+			switch {
+			case s > mr.Score[leader]:
+				leader, tied = i, false
+			case i != leader && s == mr.Score[leader] &&
+				!(opts.Game.Partnerships && i%2 == leader%2):
+				tied = true
+			}
+		}
+		if mr.Score[leader] < target || tied && opts.Tie == PlayOn {
+			continue
+		}
+		if !tied {
+			mr.Winner = leader
+		}
+		return mr, nil
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlayMatch(t *testing.T) {
+	for _, target := range []int{0, 11, 21, 61} {
+		opts := MatchOptions{Target: target, Seed: 7}
+		mr, err := PlayMatch(opts, &trailer{}, &trailer{})
+		if err != nil {
+			t.Fatalf("PlayMatch(%v): %v", target, err)
+		}
+		if target == 0 {
+			target = 21
+		}
+		sum := []int{0, 0}
+		for i, r := range mr.Games {
+			if r.Dealer != i%2 {
+				t.Errorf("PlayMatch(%v): game %v dealt by %v", target, i, r.Dealer)
+			}
+			sum[0] += r.Score[0]
+			sum[1] += r.Score[1]
+		}
+		if !reflect.DeepEqual(sum, mr.Score) {
+			t.Errorf("PlayMatch(%v): got total %v, games sum to %v", target, mr.Score, sum)
+		}
+		w := mr.Winner
+		if w < 0 || mr.Score[w] < target || mr.Score[w] <= mr.Score[1-w] {
+			t.Errorf("PlayMatch(%v): got winner %v with score %v", target, w, mr.Score)
+		}
+		again, err := PlayMatch(opts, &trailer{}, &trailer{})
+		if err != nil {
+			t.Fatalf("PlayMatch(%v): %v", target, err)
+		}
+		if !reflect.DeepEqual(again, mr) {
+			t.Errorf("PlayMatch(%v): results differ with the same seed", target)
+		}
+	}
+}
+
+func TestPlayMatchError(t *testing.T) {
+	mr, err := PlayMatch(MatchOptions{Seed: 1}, &fumbler{}, &trailer{})
+	if err == nil || len(mr.Games) != 0 || mr.Winner != -1 {
+		t.Errorf("PlayMatch: got %+v, %v, expected error", mr, err)
+	}
+}
+
+func TestPlayMatchInvalid(t *testing.T) {
+	for _, players := range [][]Player{nil, {&trailer{}}} {
+		if _, err := PlayMatch(MatchOptions{Seed: 1}, players...); err == nil {
+			t.Errorf("PlayMatch with %v players: got nil error", len(players))
+		}
+	}
+}
+
+func TestPlayMatchPartnerships(t *testing.T) {
+	players := []Player{&trailer{}, &trailer{}, &trailer{}, &trailer{}}
+	opts := MatchOptions{Seed: 3, Game: Options{Partnerships: true}}
+	mr, err := PlayMatch(opts, players...)
+	if err != nil {
+		t.Fatalf("PlayMatch: %v", err)
+	}
+	for i, r := range mr.Games {
+		if r.Dealer != i%4 {
+			t.Errorf("PlayMatch: game %v dealt by %v", i, r.Dealer)
+		}
+	}
+	w := mr.Winner
+	if w != 0 && w != 1 || mr.Score[w] < 21 || mr.Score[w] <= mr.Score[1-w] {
+		t.Errorf("PlayMatch: got winner %v with score %v", w, mr.Score)
+	}
+}
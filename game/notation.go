@@ -0,0 +1,256 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// Actions are written in move notation as follows:
+//
+//	trail ♥7
+//	capture ♦7 with ♥7
+//	capture ♣3, ♦A+[♠2 ♥4] with ♥7
+//	build 9: ♣2 onto ♦3+♥4
+//	build 8: ♥3 onto [♦2 ♠3]
+//	build 7: ♥7 and ♦7
+//	build 10: ♣2 onto ♦8 and ♥10
+//
+// A capture lists the sets of Piles it captures, separated by commas. Within
+// a set, Piles whose values are summed are joined by "+". A build names the
+// Piles in Add after "onto" and the Piles in Sets after "and". Its value is
+// optional when parsing, but if given it must match the sum of the first set,
+// or if there are no Sets, the card plus the Piles in Add, counting an ace as
+// 1 as the game does.
+//
+// A Pile is written as its card if it contains one card, or as its cards in
+// brackets if it is a build. When parsing, a Pile may also be named by any
+// card it contains or by its ID, and cards may be written in any form
+// accepted by card.Parse.
+
+// ParseAction parses an Action written in move notation. piles is the table
+// on which the Action is taken; the Piles it names are resolved to their IDs.
+// ParseAction does not check whether the Action is valid.
+func ParseAction(s string, piles map[int]Pile) (Action, error) {
+	verb, rest := cut(strings.TrimSpace(s), " ")
+	rest = strings.TrimSpace(rest)
+	switch strings.ToLower(verb) {
+	case "trail":
+		c, err := card.Parse(rest)
+		if err != nil {
+			return Action{}, err
+		}
+		return Action{Card: c}, nil
+	case "capture":
+		i := strings.LastIndex(rest, " with ")
+		if i < 0 {
+			return Action{}, fmt.Errorf("capture: missing %q", "with")
+		}
+		c, err := card.Parse(rest[i+len(" with "):])
+		if err != nil {
+			return Action{}, err
+		}
+		sets, err := parseSets(rest[:i], piles)
+		if err != nil {
+			return Action{}, err
+		}
+		return Action{Card: c, Sets: sets}, nil
+	case "build":
+		value := -1
+		if i := strings.Index(rest, ":"); i >= 0 {
+			n, err := strconv.Atoi(strings.TrimSpace(rest[:i]))
+			if err != nil {
+				return Action{}, fmt.Errorf("build: invalid value %q", rest[:i])
+			}
+			value, rest = n, strings.TrimSpace(rest[i+1:])
+		}
+		a := Action{Build: true}
+		move, sets := cut(rest, " and ")
+		if sets != "" {
+			var err error
+			if a.Sets, err = parseSets(sets, piles); err != nil {
+				return Action{}, err
+			}
+		}
+		cs, add := cut(move, " onto ")
+		if add != "" {
+			var err error
+			if a.Add, err = parseSet(strings.ReplaceAll(add, ",", "+"), piles); err != nil {
+				return Action{}, err
+			}
+		}
+		c, err := card.Parse(cs)
+		if err != nil {
+			return Action{}, err
+		}
+		a.Card = c
+		if len(a.Add) == 0 && len(a.Sets) == 0 {
+			return Action{}, fmt.Errorf("build: no piles")
+		}
+		if v := buildValue(a, piles); value >= 0 && value != v {
+			return Action{}, fmt.Errorf("build: value %v, expected %v", value, v)
+		}
+		return a, nil
+	}
+	return Action{}, fmt.Errorf("unknown move %q", verb)
+}
+
+// FormatAction returns a in move notation. piles is the table on which a is
+// taken. For an Action in the canonical form described by LegalActions,
+// ParseAction parses the result into an Action deeply equal to a.
+func FormatAction(a Action, piles map[int]Pile) string {
+	if len(a.Add) == 0 && len(a.Sets) == 0 {
+		return fmt.Sprintf("trail %v", a.Card)
+	}
+	if !a.isBuild() {
+		return fmt.Sprintf("capture %v with %v", formatSets(a.Sets, piles), a.Card)
+	}
+	s := fmt.Sprintf("build %v: %v", buildValue(a, piles), a.Card)
+	if len(a.Add) > 0 {
+		s += " onto " + formatSets([][]int{a.Add}, piles)
+	}
+	if len(a.Sets) > 0 {
+		s += " and " + formatSets(a.Sets, piles)
+	}
+	return s
+}
+
+// buildValue returns the value of build Action a: the sum of its first set
+// if it has Sets, and otherwise the rank of its card plus the values of the
+// Piles in Add. An ace counts as 1.
+func buildValue(a Action, piles map[int]Pile) int {
+	if len(a.Sets) > 0 {
+		var value int
+		for _, id := range a.Sets[0] {
+			value += piles[id].Value
+		}
+		return value
+	}
+	value := int(a.Card.Rank())
+	for _, id := range a.Add {
+		value += piles[id].Value
+	}
+	return value
+}
+
+// parseSets parses sets of Piles separated by commas.
+func parseSets(s string, piles map[int]Pile) ([][]int, error) {
+	var sets [][]int
+	for _, f := range strings.Split(s, ",") {
+		set, err := parseSet(f, piles)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// parseSet parses a set of Piles joined by "+".
+func parseSet(s string, piles map[int]Pile) ([]int, error) {
+	var ids []int
+	for _, f := range strings.Split(s, "+") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		id, err := parsePile(f, piles)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("missing pile")
+	}
+	return ids, nil
+}
+
+// parsePile parses a Pile named by its ID, by a card it contains, or by its
+// cards in brackets, and returns its ID.
+func parsePile(s string, piles map[int]Pile) (int, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	var cards []card.Card
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	for i := 0; i < len(fields); i++ {
+		c, err := card.Parse(fields[i])
+		if err != nil && i+1 < len(fields) {
+			// The card may be named in two words, as in "Big Cassino".
+			if c, err = card.Parse(fields[i] + " " + fields[i+1]); err == nil {
+				i++
+			}
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid pile %q", s)
+		}
+		cards = append(cards, c)
+	}
+	if len(cards) == 0 {
+		return 0, fmt.Errorf("invalid pile %q", s)
+	}
+	for id, p := range piles {
+		if containsAll(p.Cards, cards) {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("no pile %v on the table", s)
+}
+
+// containsAll reports whether cards contains each card in sub.
+func containsAll(cards, sub []card.Card) bool {
+	for _, s := range sub {
+		var found bool
+		for _, c := range cards {
+			if c == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// formatSets returns sets of Piles in move notation.
+func formatSets(sets [][]int, piles map[int]Pile) string {
+	var ss []string
+	for _, set := range sets {
+		var ps []string
+		for _, id := range set {
+			ps = append(ps, formatPile(id, piles))
+		}
+		ss = append(ss, strings.Join(ps, "+"))
+	}
+	return strings.Join(ss, ", ")
+}
+
+// formatPile returns a Pile in move notation, or its ID if it is not in
+// piles.
+func formatPile(id int, piles map[int]Pile) string {
+	p, ok := piles[id]
+	switch {
+	case !ok || len(p.Cards) == 0:
+		return strconv.Itoa(id)
+	case len(p.Cards) == 1:
+		return p.Cards[0].String()
+	}
+	ss := make([]string, len(p.Cards))
+	for i, c := range p.Cards {
+		ss[i] = c.String()
+	}
+	return "[" + strings.Join(ss, " ") + "]"
+}
+
+// cut slices s around the first instance of sep, returning the text before
+// and after sep. If sep does not appear in s, cut returns s, "".
+func cut(s, sep string) (before, after string) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):]
+	}
+	return s, ""
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// notationPiles is a table for notation tests.
+var notationPiles = map[int]Pile{
+	1: {Cards: []card.Card{24}, Value: 7},                     // ♣7
+	2: {Cards: []card.Card{8}, Value: 3},                      // ♣3
+	3: {Cards: []card.Card{13}, Value: 4},                     // ♦4
+	4: {Cards: []card.Card{7, 14}, Value: 6},                  // ♠2 ♥4
+	5: {Cards: []card.Card{47}},                               // ♠Q
+	6: {Cards: []card.Card{25, 27}, Value: 7, Compound: true}, // ♦7 ♠7
+}
+
+var notationTests = []struct {
+	s string
+	a Action
+}{
+	{"trail ♥7", Action{Card: 26}},
+	{"capture ♣7 with ♥7", Action{Card: 26, Sets: [][]int{{1}}}},
+	{"capture ♣7, ♣3+♦4, [♦7 ♠7] with ♥7", Action{Card: 26, Sets: [][]int{{1}, {2, 3}, {6}}}},
+	{"capture ♠Q with ♥Q", Action{Card: 46, Sets: [][]int{{5}}}},
+	{"build 9: ♣2 onto ♣3+♦4", Action{Card: 4, Add: []int{2, 3}, Build: true}},
+	{"build 8: ♦2 onto [♠2 ♥4]", Action{Card: 5, Add: []int{4}, Build: true}},
+	{"build 7: ♥7 and ♣7", Action{Card: 26, Sets: [][]int{{1}}, Build: true}},
+	{"build 7: ♥A onto [♠2 ♥4] and ♣7, ♣3+♦4", Action{Card: 2, Add: []int{4}, Sets: [][]int{{1}, {2, 3}}, Build: true}},
+}
+
+func TestParseAction(t *testing.T) {
+	for _, test := range notationTests {
+		a, err := ParseAction(test.s, notationPiles)
+		if err != nil {
+			t.Errorf("ParseAction(%q): %v", test.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(a, test.a) {
+			t.Errorf("ParseAction(%q) = %+v, expected %+v", test.s, a, test.a)
+		}
+	}
+	for s, a := range map[string]Action{
+		"trail h7":                                   {Card: 26},
+		"TRAIL 7H":                                   {Card: 26},
+		"trail 10d":                                  {Card: card.BigCassino},
+		"capture 1 with ♥7":                          {Card: 26, Sets: [][]int{{1}}},
+		"capture ♠7 with ♥7":                         {Card: 26, Sets: [][]int{{6}}},
+		"capture 1 , 2 + 3 with 7h":                  {Card: 26, Sets: [][]int{{1}, {2, 3}}},
+		"build 9: ♣2 onto 2,3":                       {Card: 4, Add: []int{2, 3}, Build: true},
+		"build ♣2 onto ♣3+♦4":                        {Card: 4, Add: []int{2, 3}, Build: true},
+		"build 8: ♦2 onto ♥4":                        {Card: 5, Add: []int{4}, Build: true},
+		"build 8: ♦2 onto Little Cassino":            {Card: 5, Add: []int{4}, Build: true},
+		"build 8: ♦2 onto [♥4 little  cassino]":      {Card: 5, Add: []int{4}, Build: true},
+		"capture little cassino+♦4 with Big Cassino": {Card: card.BigCassino, Sets: [][]int{{4, 3}}},
+		"capture 99 with ♥7":                         {Card: 26, Sets: [][]int{{99}}},
+	} {
+		got, err := ParseAction(s, notationPiles)
+		if err != nil || !reflect.DeepEqual(got, a) {
+			t.Errorf("ParseAction(%q) = %+v, %v; expected %+v", s, got, err, a)
+		}
+	}
+	for _, s := range []string{
+		"",
+		"fish ♥7",
+		"trail",
+		"trail ♥1",
+		"trail x7",
+		"capture ♣7",
+		"capture with ♥7",
+		"capture ♣7,,♣3 with ♥7",
+		"capture ♥9 with ♥7",
+		"capture [♣7 ♣3] with ♥7",
+		"capture Big Cassino with ♥10",
+		"capture Little with ♥7",
+		"build ♥7",
+		"build x: ♥7 onto ♣7",
+		"build 5: ♣2 onto ♣3+♦4",
+		"build 8: ♥7 and ♣7",
+		"build 6: ♥A onto [♠2 ♥4] and ♣7",
+		"build 20: ♥A onto [♠2 ♥4]",
+	} {
+		if a, err := ParseAction(s, notationPiles); err == nil {
+			t.Errorf("ParseAction(%q) = %+v, expected error", s, a)
+		}
+	}
+}
+
+func TestFormatAction(t *testing.T) {
+	for _, test := range notationTests {
+		if s := FormatAction(test.a, notationPiles); s != test.s {
+			t.Errorf("FormatAction(%+v) = %q, expected %q", test.a, s, test.s)
+		}
+	}
+	if s, want := FormatAction(Action{Card: 26, Sets: [][]int{{99}}}, notationPiles), "capture 99 with ♥7"; s != want {
+		t.Errorf("FormatAction with unknown pile = %q, expected %q", s, want)
+	}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, r := range []Rules{{}, {Royal: true}} {
+		for n := 0; n < 2000; n++ {
+			g := randomTable(rng, r)
+			for _, a := range r.LegalActions(g.hand[0].Cards(), g.piles, 0) {
+				s := FormatAction(a, g.piles)
+				got, err := ParseAction(s, g.piles)
+				if err != nil || !reflect.DeepEqual(got, a) {
+					t.Fatalf("ParseAction(FormatAction(%+v)) = ParseAction(%q) = %+v, %v", a, s, got, err)
+				}
+			}
+		}
+	}
+}
+
+// miscounter is a trailer that attempts to capture Pile 1 with its first card.
+type miscounter struct{ trailer }
+
+func (m *miscounter) Play(piles map[int]Pile) Action {
+	return Action{Card: m.hand[0], Sets: [][]int{{1}}}
+}
+
+func TestActionErrorNotation(t *testing.T) {
+	deck := make([]card.Card, 52)
+	for i := range deck {
+		deck[i] = card.Card(i)
+	}
+	// The table holds the aces, and player 1 holds the twos.
+	_, err := PlayGame(Options{Deck: deck}, &trailer{}, &miscounter{})
+	var ae *ActionError
+	if !errors.As(err, &ae) {
+		t.Fatalf("PlayGame: got %v, expected *ActionError", err)
+	}
+	want := "player 1 with hand [♣2 ♦2 ♥2 ♠2]: invalid action capture ♣A with ♣2: invalid set sum: ♣A (sum 1) using ♣2"
+	if err.Error() != want {
+		t.Errorf("got error %q, expected %q", err, want)
+	}
+	if !errors.Is(err, ErrBadSetSum) {
+		t.Errorf("error %q does not wrap %q", err, ErrBadSetSum)
+	}
+}
@@ -5,10 +5,14 @@ import "github.com/dkmccandless/cassino/card"
 // A Player can participate in a game of Cassino.
 type Player interface {
 	// Init informs the Player of the initial state of the game.
-	// pos is the Player's position in the order of play.
-	Init(pos int, piles map[int]Pile)
+	// pos is the Player's position and dealer is the dealer's position.
+	// Play begins with the player after the dealer.
+	Init(pos, dealer int, piles map[int]Pile)
 
-	// Hand supplies a new hand of four cards.
+	// Hand supplies cards dealt to the Player, which join any cards already
+	// in their hand. Each round begins with a hand of four cards; under
+	// Rules.Draw, Hand is also called with the single card the Player draws
+	// after each of their turns.
 	Hand(hand []card.Card)
 
 	// Note informs the Player of cards their opponent plays and captures.
@@ -18,3 +22,23 @@ type Player interface {
 	// Play reports the Action the player takes on their turn.
 	Play(piles map[int]Pile) Action
 }
+
+// A TurnNoter is a Player that is informed of each Turn in full.
+// NoteTurn is called after every Turn, including the Player's own, and
+// replaces calls to Note.
+type TurnNoter interface {
+	NoteTurn(t Turn)
+}
+
+// A HandEnder is a Player that is informed when each round ends.
+// EndHand reports the number of cards left in the deck; if it is zero, the
+// game is over and the remaining cards on the table go to the player who
+// captured last.
+type HandEnder interface {
+	EndHand(deck int)
+}
+
+// A GameEnder is a Player that is informed of the result of the game.
+type GameEnder interface {
+	End(r Result)
+}
@@ -0,0 +1,83 @@
+package game
+
+import "github.com/dkmccandless/cassino/card"
+
+// Rules selects a variant of Cassino. The zero Rules are the basic rules
+// described in the README.
+type Rules struct {
+	// Royal plays Royal Cassino: jacks, queens, and kings have values 11, 12,
+	// and 13 and may be used in builds like number cards, and an ace played
+	// from the hand may count as 1 or 14. Aces on the table count as 1.
+	Royal bool `json:"royal,omitempty"`
+
+	// SpadeCassino plays Spade Cassino: instead of the point for most
+	// spades, each spade captured scores 1 point, and the jack of spades and
+	// Little Cassino score 2.
+	SpadeCassino bool `json:"spadeCassino,omitempty"`
+
+	// Draw plays Draw Cassino: instead of being dealt a new hand each round,
+	// each player draws a card from the deck after each turn until the deck
+	// is exhausted.
+	Draw bool `json:"draw,omitempty"`
+
+	// NoSweeps awards no points for sweeps.
+	NoSweeps bool `json:"noSweeps,omitempty"`
+
+	// TrailWhileBuilding allows a player who controls a build to trail.
+	TrailWhileBuilding bool `json:"trailWhileBuilding,omitempty"`
+}
+
+// Values returns the values a card may take when played from the hand, in
+// ascending order. Under the basic rules, face cards have no value.
+func (r Rules) Values(c card.Card) []int {
+	switch {
+	case r.Royal && c.IsAce():
+		return []int{1, 14}
+	case r.isFace(c):
+		return nil
+	}
+	return []int{int(c.Rank())}
+}
+
+// HasValue reports whether c may take the value v when played from the hand.
+func (r Rules) HasValue(c card.Card, v int) bool {
+	for _, cv := range r.Values(c) {
+		if cv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of a Pile containing the single card c.
+func (r Rules) value(c card.Card) int {
+	if r.isFace(c) {
+		return 0
+	}
+	return int(c.Rank())
+}
+
+// maxValue returns the greatest value a card may take.
+func (r Rules) maxValue() int {
+	if r.Royal {
+		return 14
+	}
+	return 10
+}
+
+// isFace reports whether c is a face card that has no value and can only
+// capture cards of the same rank.
+func (r Rules) isFace(c card.Card) bool {
+	return !r.Royal && c.IsFace()
+}
+
+// haveValue reports whether hand contains a card that may take the value v
+// besides the excluded card.
+func (r Rules) haveValue(hand card.Set, v int, exclude card.Card) bool {
+	for _, c := range hand.Cards() {
+		if c != exclude && r.HasValue(c, v) {
+			return true
+		}
+	}
+	return false
+}
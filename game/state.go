@@ -0,0 +1,396 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// A State is the complete state of a game in progress: the cards in each
+// player's hand, in the deck, on the table, and captured, and the player to
+// move. Apply advances a State by one turn, dealing and scoring as the game
+// does, so a State may be cloned and played ahead to search for an Action.
+type State struct {
+	rules        Rules
+	partnerships bool
+
+	// dealer is the player who dealt.
+	dealer int
+
+	// turn is the player to move.
+	turn int
+
+	// hand contains the cards in each player's hand.
+	hand []card.Set
+
+	// keep contains the cards captured by each player.
+	keep [][]card.Card
+
+	// sweeps records how many sweeps each player has made.
+	sweeps []int
+
+	// deck contains the cards not yet dealt.
+	deck *card.Deck
+
+	// piles contains the cards on the table.
+	piles map[int]Pile
+
+	// npiles records how many Piles have been added.
+	npiles int
+
+	// lastCapture records who played the most recent capture.
+	// It is initially the dealer.
+	lastCapture int
+}
+
+// ErrGameOver is returned by Apply when the game has ended.
+var ErrGameOver = errors.New("game over")
+
+// NewState deals a game among the given number of players according to opts
+// and returns its State. The Policy and Retries in opts are ignored.
+func NewState(opts Options, players int) (*State, error) {
+	if err := validateOptions(opts, players); err != nil {
+		return nil, err
+	}
+	deck, _, err := newDeck(opts)
+	if err != nil {
+		return nil, err
+	}
+	s := newState(opts, players, deck)
+	s.dealRound(nopObserver{})
+	return s, nil
+}
+
+// newState returns the State of a game among the given number of players
+// after the table has been dealt from deck.
+func newState(opts Options, players int, deck *card.Deck) *State {
+	s := &State{
+		rules:        opts.Rules,
+		partnerships: opts.Partnerships,
+		dealer:       opts.Dealer,
+		hand:         make([]card.Set, players),
+		keep:         make([][]card.Card, players),
+		sweeps:       make([]int, players),
+		deck:         deck,
+		piles:        make(map[int]Pile),
+		lastCapture:  opts.Dealer,
+	}
+	for _, c := range deck.Deal(4) {
+		s.addCardPile(c)
+	}
+	return s
+}
+
+// Clone returns a State that does not share memory with s.
+func (s *State) Clone() *State {
+	c := *s
+	c.hand = append([]card.Set(nil), s.hand...)
+	c.keep = make([][]card.Card, len(s.keep))
+	for i, k := range s.keep {
+		c.keep[i] = append([]card.Card(nil), k...)
+	}
+	c.sweeps = append([]int(nil), s.sweeps...)
+	if s.deck != nil {
+		c.deck = card.DeckOf(s.deck.Cards())
+	}
+	c.piles = s.copyPiles()
+	return &c
+}
+
+// Apply takes Action a for the player to move and advances the game: in Draw
+// Cassino the player draws a card, a new round is dealt when every hand is
+// empty, and at the end of the game the cards left on the table go to the
+// player who captured last. If a is invalid, Apply returns an error wrapping
+// one of the Err values and leaves s unchanged.
+func (s *State) Apply(a Action) error {
+	if s.IsTerminal() {
+		return ErrGameOver
+	}
+	if err := s.validateAction(s.turn, a); err != nil {
+		return err
+	}
+	s.apply(a, nopObserver{})
+	return nil
+}
+
+// Legal returns every valid Action for the player to move, in the canonical
+// form described by LegalActions. It returns nil if the game has ended.
+func (s *State) Legal() []Action {
+	if s.IsTerminal() {
+		return nil
+	}
+	return s.rules.LegalActions(s.hand[s.turn].Cards(), s.piles, s.turn)
+}
+
+// IsTerminal reports whether the game has ended.
+func (s *State) IsTerminal() bool {
+	for _, h := range s.hand {
+		if h != 0 {
+			return false
+		}
+	}
+	return s.deck == nil || s.deck.Remaining() == 0
+}
+
+// Score returns each player's score for the cards captured and sweeps made
+// so far. Partners share a score. Once the game has ended, it is the final
+// score.
+func (s *State) Score() []int {
+	var score []int
+	for _, b := range s.breakdown() {
+		score = append(score, b.Total())
+	}
+	return score
+}
+
+// Rules returns the rules of the game.
+func (s *State) Rules() Rules { return s.rules }
+
+// Turn returns the player to move.
+func (s *State) Turn() int { return s.turn }
+
+// Hand returns the cards in player's hand in ascending order.
+func (s *State) Hand(player int) []card.Card { return s.hand[player].Cards() }
+
+// Piles returns a copy of the Piles on the table.
+func (s *State) Piles() map[int]Pile { return s.copyPiles() }
+
+// A Snapshot records the contents of a State in a form that may be inspected,
+// modified, and encoded. Restore returns the State a Snapshot describes.
+type Snapshot struct {
+	Rules        Rules `json:"rules"`
+	Partnerships bool  `json:"partnerships,omitempty"`
+
+	// Dealer is the player who dealt.
+	Dealer int `json:"dealer"`
+
+	// Turn is the player to move.
+	Turn int `json:"turn"`
+
+	// Hands lists the cards in each player's hand.
+	Hands [][]card.Card `json:"hands"`
+
+	// Keeps lists the cards captured by each player.
+	Keeps [][]card.Card `json:"keeps"`
+
+	// Sweeps records how many sweeps each player has made.
+	Sweeps []int `json:"sweeps"`
+
+	// Deck lists the cards not yet dealt in the order they will be dealt.
+	Deck []card.Card `json:"deck"`
+
+	// Piles contains the cards on the table.
+	Piles map[int]Pile `json:"piles"`
+
+	// NPiles records how many Piles have been added. New Piles are given
+	// IDs greater than NPiles.
+	NPiles int `json:"npiles"`
+
+	// LastCapture is the player who played the most recent capture, or the
+	// dealer if no player has captured.
+	LastCapture int `json:"lastCapture"`
+}
+
+// Snapshot returns the contents of s.
+func (s *State) Snapshot() Snapshot {
+	snap := Snapshot{
+		Rules:        s.rules,
+		Partnerships: s.partnerships,
+		Dealer:       s.dealer,
+		Turn:         s.turn,
+		Hands:        make([][]card.Card, len(s.hand)),
+		Keeps:        make([][]card.Card, len(s.keep)),
+		Sweeps:       append([]int(nil), s.sweeps...),
+		Piles:        s.copyPiles(),
+		NPiles:       s.npiles,
+		LastCapture:  s.lastCapture,
+	}
+	for i, h := range s.hand {
+		snap.Hands[i] = h.Cards()
+	}
+	for i, k := range s.keep {
+		snap.Keeps[i] = append([]card.Card{}, k...)
+	}
+	if s.deck != nil {
+		snap.Deck = s.deck.Cards()
+	}
+	return snap
+}
+
+// Restore returns the State described by snap. It returns an error if snap
+// does not describe a game in progress: if a card appears more than once, a
+// Pile ID is out of range, or the player to move holds no cards while another
+// player does. The cards need not include the whole deck. If every hand and
+// the deck are empty, the cards left on the table go to snap.LastCapture.
+func Restore(snap Snapshot) (*State, error) {
+	n := len(snap.Hands)
+	if err := validateOptions(Options{Dealer: snap.Dealer, Partnerships: snap.Partnerships}, n); err != nil {
+		return nil, err
+	}
+	switch {
+	case snap.Keeps != nil && len(snap.Keeps) != n:
+		return nil, fmt.Errorf("invalid snapshot: %v keeps for %v players", len(snap.Keeps), n)
+	case snap.Sweeps != nil && len(snap.Sweeps) != n:
+		return nil, fmt.Errorf("invalid snapshot: %v sweeps for %v players", len(snap.Sweeps), n)
+	case snap.Turn < 0 || snap.Turn >= n:
+		return nil, fmt.Errorf("invalid snapshot: invalid turn %v", snap.Turn)
+	case snap.LastCapture < 0 || snap.LastCapture >= n:
+		return nil, fmt.Errorf("invalid snapshot: invalid last capture %v", snap.LastCapture)
+	}
+
+	var seen card.Set
+	add := func(cards []card.Card) error {
+		for _, c := range cards {
+			if !c.Valid() {
+				return fmt.Errorf("invalid snapshot: invalid card %d", int(c))
+			}
+			if seen.Contains(c) {
+				return fmt.Errorf("invalid snapshot: duplicate card %v", c)
+			}
+			seen = seen.Add(c)
+		}
+		return nil
+	}
+	s := &State{
+		rules:        snap.Rules,
+		partnerships: snap.Partnerships,
+		dealer:       snap.Dealer,
+		turn:         snap.Turn,
+		hand:         make([]card.Set, n),
+		keep:         make([][]card.Card, n),
+		sweeps:       make([]int, n),
+		deck:         card.DeckOf(snap.Deck),
+		piles:        make(map[int]Pile, len(snap.Piles)),
+		npiles:       snap.NPiles,
+		lastCapture:  snap.LastCapture,
+	}
+	for i, h := range snap.Hands {
+		if err := add(h); err != nil {
+			return nil, err
+		}
+		s.hand[i] = card.NewSet(h...)
+	}
+	for i, k := range snap.Keeps {
+		if err := add(k); err != nil {
+			return nil, err
+		}
+		s.keep[i] = append([]card.Card{}, k...)
+	}
+	copy(s.sweeps, snap.Sweeps)
+	if err := add(snap.Deck); err != nil {
+		return nil, err
+	}
+	for id, p := range snap.Piles {
+		if id <= 0 || id > snap.NPiles {
+			return nil, fmt.Errorf("invalid snapshot: invalid pile ID %v", id)
+		}
+		if len(p.Cards) == 0 {
+			return nil, fmt.Errorf("invalid snapshot: empty pile %v", id)
+		}
+		if err := add(p.Cards); err != nil {
+			return nil, err
+		}
+		s.piles[id] = copyPile(p)
+	}
+
+	if s.hand[s.turn] == 0 {
+		for i, h := range s.hand {
+			if h != 0 {
+				return nil, fmt.Errorf("invalid snapshot: player %v to move holds no cards, but player %v does", s.turn, i)
+			}
+		}
+		if s.deck.Remaining() != 0 {
+			return nil, fmt.Errorf("invalid snapshot: no cards in hand with %v cards in the deck", s.deck.Remaining())
+		}
+		s.finish(nopObserver{})
+	}
+	return s, nil
+}
+
+// An observer is informed of each step as a State advances.
+type observer interface {
+	noteTurn(t Turn)
+	noteDeal(player int, cards []card.Card)
+	noteEndHand()
+	noteClear(player int, cards []card.Card)
+}
+
+// A nopObserver is an observer that ignores the steps of a State.
+type nopObserver struct{}
+
+func (nopObserver) noteTurn(t Turn)                         {}
+func (nopObserver) noteDeal(player int, cards []card.Card)  {}
+func (nopObserver) noteEndHand()                            {}
+func (nopObserver) noteClear(player int, cards []card.Card) {}
+
+// apply performs a valid Action for the player to move and advances the game
+// to the next player's turn or its end, informing obs of each step.
+func (s *State) apply(a Action, obs observer) {
+	player := s.turn
+	obs.noteTurn(s.do(player, a))
+	if s.rules.Draw && s.deck.Remaining() != 0 {
+		s.dealTo(player, 1, obs)
+	}
+	n := len(s.hand)
+	for k := 1; k <= n; k++ {
+		if i := (player + k) % n; s.hand[i] != 0 {
+			s.turn = i
+			return
+		}
+	}
+	obs.noteEndHand()
+	if s.deck.Remaining() != 0 {
+		s.dealRound(obs)
+		return
+	}
+	s.finish(obs)
+}
+
+// dealRound deals four cards to each player, beginning with the player after
+// the dealer, who moves first.
+func (s *State) dealRound(obs observer) {
+	for _, i := range s.order() {
+		s.dealTo(i, 4, obs)
+	}
+	s.turn = (s.dealer + 1) % len(s.hand)
+}
+
+// dealTo deals n cards to a player.
+func (s *State) dealTo(player, n int, obs observer) {
+	cards := s.deck.Deal(n)
+	s.hand[player] = s.hand[player].Union(card.NewSet(cards...))
+	obs.noteDeal(player, cards)
+}
+
+// finish awards the cards left on the table to the player who captured last.
+func (s *State) finish(obs observer) {
+	var cards []card.Card
+	for _, id := range s.pileIDs() {
+		cards = append(cards, s.piles[id].Cards...)
+		s.capture(s.lastCapture, id)
+	}
+	obs.noteClear(s.lastCapture, cards)
+}
+
+// breakdown itemizes each player's score for the cards captured and sweeps
+// made so far. Partners share a ScoreBreakdown.
+func (s *State) breakdown() []ScoreBreakdown {
+	nteams := len(s.hand)
+	if s.partnerships {
+		nteams = 2
+	}
+	keeps, sweeps := make([][]card.Card, nteams), make([]int, nteams)
+	for i := range s.hand {
+		t := s.team(i)
+		keeps[t] = append(keeps[t], s.keep[i]...)
+		sweeps[t] += s.sweeps[i]
+	}
+	scores := s.rules.score(keeps, sweeps)
+	bs := make([]ScoreBreakdown, len(s.hand))
+	for i := range s.hand {
+		bs[i] = scores[s.team(i)]
+		bs[i].Scoring = append([]card.Card(nil), bs[i].Scoring...)
+	}
+	return bs
+}
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// wanderer is a Player that takes a random legal Action.
+type wanderer struct {
+	rng   *rand.Rand
+	rules Rules
+	pos   int
+	hand  []card.Card
+}
+
+func (w *wanderer) Init(pos, dealer int, piles map[int]Pile) { w.pos = pos }
+func (w *wanderer) Hand(hand []card.Card)                    { w.hand = append(w.hand, hand...) }
+func (w *wanderer) Note(card.Card, []card.Card)              {}
+func (w *wanderer) Play(piles map[int]Pile) Action {
+	actions := w.rules.LegalActions(w.hand, piles, w.pos)
+	a := actions[w.rng.Intn(len(actions))]
+	for i, c := range w.hand {
+		if c == a.Card {
+			w.hand = append(w.hand[:i], w.hand[i+1:]...)
+			break
+		}
+	}
+	return a
+}
+
+var stateTests = []struct {
+	opts    Options
+	players int
+}{
+	{Options{Seed: 1}, 2},
+	{Options{Seed: 2, Dealer: 1}, 3},
+	{Options{Seed: 3, Dealer: 3, Partnerships: true}, 4},
+	{Options{Seed: 4, Rules: Rules{Royal: true, SpadeCassino: true}}, 2},
+	{Options{Seed: 5, Rules: Rules{Draw: true}}, 3},
+	{Options{Seed: 6, Rules: Rules{TrailWhileBuilding: true, NoSweeps: true}}, 2},
+}
+
+func TestStateMatchesPlayGame(t *testing.T) {
+	for _, test := range stateTests {
+		rng := rand.New(rand.NewSource(test.opts.Seed))
+		players := make([]Player, test.players)
+		for i := range players {
+			players[i] = &wanderer{rng: rng, rules: test.opts.Rules}
+		}
+		r, err := PlayGame(test.opts, players...)
+		if err != nil {
+			t.Fatalf("PlayGame(%+v): %v", test.opts, err)
+		}
+
+		s, err := NewState(test.opts, test.players)
+		if err != nil {
+			t.Fatalf("NewState(%+v): %v", test.opts, err)
+		}
+		for _, e := range r.Log {
+			if e.Type != EventTurn {
+				continue
+			}
+			if s.Turn() != e.Player {
+				t.Fatalf("%+v: got turn %v, expected %v", test.opts, s.Turn(), e.Player)
+			}
+			var legal bool
+			for _, a := range s.Legal() {
+				legal = legal || reflect.DeepEqual(a, e.Turn.Action)
+			}
+			if !legal {
+				t.Errorf("%+v: Legal() does not include %v", test.opts, FormatAction(e.Turn.Action, s.piles))
+			}
+			if err := s.Apply(e.Turn.Action); err != nil {
+				t.Fatalf("%+v: Apply: %v", test.opts, err)
+			}
+		}
+		if !s.IsTerminal() {
+			t.Errorf("%+v: game not over after the last turn", test.opts)
+		}
+		if got := s.Score(); !reflect.DeepEqual(got, r.Score) {
+			t.Errorf("%+v: got score %v, expected %v", test.opts, got, r.Score)
+		}
+		if !reflect.DeepEqual(s.keep, r.Keep) {
+			t.Errorf("%+v: got keep %v, expected %v", test.opts, s.keep, r.Keep)
+		}
+		if err := s.Apply(Action{}); !errors.Is(err, ErrGameOver) {
+			t.Errorf("%+v: Apply after the game: got %v, expected %v", test.opts, err, ErrGameOver)
+		}
+		if s.Legal() != nil {
+			t.Errorf("%+v: Legal after the game: got %v, expected nil", test.opts, s.Legal())
+		}
+	}
+}
+
+func TestStateApplyInvalid(t *testing.T) {
+	s, err := NewState(Options{Seed: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := s.Snapshot()
+	err = s.Apply(Action{Card: s.Hand(0)[0]})
+	if !errors.Is(err, ErrNotInHand) {
+		t.Errorf("Apply(opponent's card): got %v, expected %v", err, ErrNotInHand)
+	}
+	if after := s.Snapshot(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Apply(opponent's card) changed the State: got %+v, expected %+v", after, before)
+	}
+}
+
+func TestStateClone(t *testing.T) {
+	s, err := NewState(Options{Seed: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := s.Snapshot()
+	c := s.Clone()
+	rng := rand.New(rand.NewSource(1))
+	for !c.IsTerminal() {
+		actions := c.Legal()
+		if err := c.Apply(actions[rng.Intn(len(actions))]); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	}
+	if after := s.Snapshot(); !reflect.DeepEqual(after, before) {
+		t.Errorf("playing a Clone changed the State: got %+v, expected %+v", after, before)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	for _, test := range stateTests {
+		s, err := NewState(test.opts, test.players)
+		if err != nil {
+			t.Fatalf("NewState(%+v): %v", test.opts, err)
+		}
+		rng := rand.New(rand.NewSource(test.opts.Seed))
+		for !s.IsTerminal() {
+			r, err := Restore(s.Snapshot())
+			if err != nil {
+				t.Fatalf("%+v: Restore: %v", test.opts, err)
+			}
+			if got, want := r.Snapshot(), s.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("%+v: Restore(Snapshot()): got %+v, expected %+v", test.opts, got, want)
+			}
+			actions := s.Legal()
+			if err := s.Apply(actions[rng.Intn(len(actions))]); err != nil {
+				t.Fatalf("%+v: Apply: %v", test.opts, err)
+			}
+		}
+	}
+}
+
+var restoreTests = map[string]Snapshot{
+	"one player": {
+		Hands: [][]card.Card{{0}},
+	},
+	"duplicate card": {
+		Hands: [][]card.Card{{0}, {1}},
+		Deck:  []card.Card{1},
+	},
+	"invalid card": {
+		Hands: [][]card.Card{{0}, {52}},
+	},
+	"invalid turn": {
+		Turn:  2,
+		Hands: [][]card.Card{{0}, {1}},
+	},
+	"invalid pile ID": {
+		Hands:  [][]card.Card{{0}, {1}},
+		Piles:  map[int]Pile{2: {Cards: []card.Card{2}, Value: 1}},
+		NPiles: 1,
+	},
+	"empty pile": {
+		Hands:  [][]card.Card{{0}, {1}},
+		Piles:  map[int]Pile{1: {}},
+		NPiles: 1,
+	},
+	"mover without cards": {
+		Hands: [][]card.Card{{}, {1}},
+	},
+	"undealt round": {
+		Hands: [][]card.Card{{}, {}},
+		Deck:  []card.Card{0, 1, 2, 3, 4, 5, 6, 7},
+	},
+	"keeps": {
+		Hands: [][]card.Card{{0}, {1}},
+		Keeps: [][]card.Card{{2}},
+	},
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	for name, snap := range restoreTests {
+		if _, err := Restore(snap); err == nil {
+			t.Errorf("Restore(%v): got nil, expected error", name)
+		}
+	}
+}
+
+func TestRestoreEnd(t *testing.T) {
+	s, err := Restore(Snapshot{
+		Hands:       [][]card.Card{{}, {}},
+		Keeps:       [][]card.Card{{card.BigCassino}, {4}},
+		Piles:       map[int]Pile{3: {Cards: []card.Card{0}, Value: 1}},
+		NPiles:      3,
+		LastCapture: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsTerminal() || len(s.piles) != 0 {
+		t.Errorf("Restore(end): got terminal %v with piles %v, expected the table cleared", s.IsTerminal(), s.piles)
+	}
+	// Player 0 has Big Cassino; player 1 has the ace and most cards.
+	if got, want := s.Score(), []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Restore(end): got score %v, expected %v", got, want)
+	}
+}
@@ -0,0 +1,347 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+// A Game is a game of Cassino driven one move at a time. Rather than asking
+// Players for their Actions, as PlayGame does, a Game waits for the Action of
+// the player to move to be submitted, which suits callers that receive moves
+// as they arrive, such as servers, user interfaces, and training loops.
+type Game struct {
+	state *State
+	opts  Options
+
+	// seed is the Seed used to shuffle the deck, if any.
+	seed int64
+
+	// deck lists the cards in the order they are dealt.
+	deck []card.Card
+
+	// clear lists the cards left on the table at the end of the game.
+	clear []card.Card
+
+	// log records the events of the game.
+	log []Event
+
+	// history records each move taken, in order.
+	history []move
+
+	// redo lists the moves undone since the last move taken, the most
+	// recently undone last.
+	redo []Action
+
+	// notify, if not nil, is also informed as the game advances.
+	notify observer
+}
+
+// A move records an Action taken and the game before it. The State it records
+// is never modified, so a move may be shared between Games.
+type move struct {
+	// state is the State before the move.
+	state *State
+
+	// log is the number of events before the move.
+	log int
+
+	// turn describes the move.
+	turn Turn
+}
+
+// Errors returned by the methods of a Game.
+var (
+	// ErrNotYourTurn is returned by Submit when a player other than the
+	// player to move submits an Action.
+	ErrNotYourTurn = errors.New("not your turn")
+
+	// ErrNoUndo is returned by Undo when no move has been taken.
+	ErrNoUndo = errors.New("no move to undo")
+
+	// ErrNoRedo is returned by Redo when no move has been undone.
+	ErrNoRedo = errors.New("no move to redo")
+)
+
+// NewGame deals a game of Cassino among two, three, or four players according
+// to opts and returns it, ready for the first Action. The Policy and Retries
+// in opts are ignored; Submit rejects every invalid Action.
+func NewGame(opts Options, players int) (*Game, error) {
+	g, err := newGame(opts, players)
+	if err != nil {
+		return nil, err
+	}
+	g.start()
+	return g, nil
+}
+
+// newGame returns a Game among the given number of players after the table
+// has been dealt, before the players' hands are dealt.
+func newGame(opts Options, players int) (*Game, error) {
+	if err := validateOptions(opts, players); err != nil {
+		return nil, err
+	}
+	deck, seed, err := newDeck(opts)
+	if err != nil {
+		return nil, err
+	}
+	g := &Game{opts: opts, seed: seed, deck: deck.Cards()}
+	g.state = newState(opts, players, deck)
+	g.emit(Event{
+		Type:         EventDeal,
+		Player:       opts.Dealer,
+		Players:      players,
+		Partnerships: opts.Partnerships,
+		Rules:        &g.opts.Rules,
+		Cards:        append([]card.Card{}, g.deck...),
+		Piles:        g.state.copyPiles(),
+	})
+	return g, nil
+}
+
+// start deals the first round.
+func (g *Game) start() {
+	g.state.dealRound(g)
+}
+
+// ToMove returns the player to move, or -1 if the game is over.
+func (g *Game) ToMove() int {
+	if g.Done() {
+		return -1
+	}
+	return g.state.turn
+}
+
+// Done reports whether the game is over.
+func (g *Game) Done() bool { return g.state.IsTerminal() }
+
+// Submit takes Action a for player. It returns ErrGameOver if the game is
+// over, an error wrapping ErrNotYourTurn if player is not the player to move,
+// and an error wrapping one of the Err values of Validate if a is invalid; in
+// each case the game is unchanged.
+func (g *Game) Submit(player int, a Action) error {
+	switch {
+	case g.Done():
+		return ErrGameOver
+	case player != g.state.turn:
+		return fmt.Errorf("%w: player %v to move", ErrNotYourTurn, g.state.turn)
+	}
+	if err := g.state.validateAction(player, a); err != nil {
+		return err
+	}
+	g.redo = nil
+	g.take(a)
+	return nil
+}
+
+// take takes a valid Action for the player to move and records it in the
+// history.
+func (g *Game) take(a Action) {
+	g.history = append(g.history, move{state: g.state.Clone(), log: len(g.log)})
+	g.state.apply(a, g)
+}
+
+// Undo takes back the last move, restoring the game exactly as it was before
+// it, and removes the move's events. The move may be taken again by Redo
+// until another Action is submitted.
+func (g *Game) Undo() error {
+	if len(g.history) == 0 {
+		return ErrNoUndo
+	}
+	m := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	g.redo = append(g.redo, m.turn.Action)
+	g.state = m.state.Clone()
+	g.log = g.log[:m.log:m.log]
+	g.clear = nil
+	return nil
+}
+
+// Redo takes the last move undone by Undo again.
+func (g *Game) Redo() error {
+	if len(g.redo) == 0 {
+		return ErrNoRedo
+	}
+	a := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.take(a)
+	return nil
+}
+
+// History returns the moves taken so far, in order.
+func (g *Game) History() []Turn {
+	turns := make([]Turn, len(g.history))
+	for i, m := range g.history {
+		turns[i] = copyTurn(m.turn)
+	}
+	return turns
+}
+
+// Branch returns a new Game that begins as g did and continues with the first
+// n moves of its history, from which a different variation may be played.
+// Moves taken in either Game do not affect the other.
+func (g *Game) Branch(n int) (*Game, error) {
+	if n < 0 || n > len(g.history) {
+		return nil, fmt.Errorf("invalid move %v of %v", n, len(g.history))
+	}
+	b := *g
+	b.history = append([]move(nil), g.history[:n]...)
+	b.redo = nil
+	b.notify = nil
+	if n == len(g.history) {
+		b.state = g.state.Clone()
+		b.log = append([]Event(nil), g.log...)
+		b.clear = append([]card.Card(nil), g.clear...)
+	} else {
+		m := g.history[n]
+		b.state = m.state.Clone()
+		b.log = append([]Event(nil), g.log[:m.log]...)
+		b.clear = nil
+	}
+	return &b, nil
+}
+
+// Legal returns every valid Action for the player to move, in the canonical
+// form described by LegalActions, or nil if the game is over.
+func (g *Game) Legal() []Action { return g.state.Legal() }
+
+// State returns a copy of the game's State.
+func (g *Game) State() *State { return g.state.Clone() }
+
+// Events returns the events of the game so far, in order. Events are
+// appended as the game advances and removed by Undo.
+func (g *Game) Events() []Event { return append([]Event(nil), g.log...) }
+
+// Result returns the result of the game and reports whether the game is over.
+func (g *Game) Result() (Result, bool) {
+	if !g.Done() {
+		return Result{}, false
+	}
+	r := Result{
+		Clear:       g.clear,
+		LastCapture: g.state.lastCapture,
+		Breakdown:   g.state.breakdown(),
+		Keep:        g.state.keep,
+		Log:         g.log,
+		Seed:        g.seed,
+		Deck:        g.deck,
+		Dealer:      g.state.dealer,
+	}
+	for _, b := range r.Breakdown {
+		r.Score = append(r.Score, b.Total())
+	}
+	return copyResult(r), true
+}
+
+// An Observation describes a game as seen by one player: their own hand and
+// everything known to all players.
+type Observation struct {
+	// Player is the observing player's position.
+	Player int `json:"player"`
+
+	// Dealer is the dealer's position.
+	Dealer int `json:"dealer"`
+
+	// ToMove is the player to move, or -1 if the game is over.
+	ToMove int `json:"toMove"`
+
+	// Rules are the rules of the game.
+	Rules Rules `json:"rules"`
+
+	// Partnerships reports whether the game is played in partnerships.
+	Partnerships bool `json:"partnerships,omitempty"`
+
+	// Hand lists the cards in the player's hand in ascending order.
+	Hand []card.Card `json:"hand"`
+
+	// Piles contains the cards on the table.
+	Piles map[int]Pile `json:"piles"`
+
+	// HandSizes records the number of cards in each player's hand.
+	HandSizes []int `json:"handSizes"`
+
+	// Deck is the number of cards not yet dealt.
+	Deck int `json:"deck"`
+
+	// Keeps lists the cards captured by each player.
+	Keeps [][]card.Card `json:"keeps"`
+
+	// Sweeps records how many sweeps each player has made.
+	Sweeps []int `json:"sweeps"`
+
+	// LastCapture is the player who captured last, or the dealer if no
+	// player has captured.
+	LastCapture int `json:"lastCapture"`
+}
+
+// Observation returns the game as seen by player. It returns an error if
+// player is not a position in the game.
+func (g *Game) Observation(player int) (Observation, error) {
+	s := g.state
+	if player < 0 || player >= len(s.hand) {
+		return Observation{}, fmt.Errorf("invalid player %v", player)
+	}
+	o := Observation{
+		Player:       player,
+		Dealer:       s.dealer,
+		ToMove:       g.ToMove(),
+		Rules:        s.rules,
+		Partnerships: s.partnerships,
+		Hand:         s.hand[player].Cards(),
+		Piles:        s.copyPiles(),
+		Deck:         s.deck.Remaining(),
+		Sweeps:       append([]int(nil), s.sweeps...),
+		LastCapture:  s.lastCapture,
+	}
+	for i, h := range s.hand {
+		o.HandSizes = append(o.HandSizes, h.Count())
+		o.Keeps = append(o.Keeps, append([]card.Card{}, s.keep[i]...))
+	}
+	return o, nil
+}
+
+// Position returns the Position of the Observation's player.
+func (o Observation) Position() Position {
+	return Position{Hand: o.Hand, Piles: o.Piles}
+}
+
+func (g *Game) noteTurn(t Turn) {
+	g.history[len(g.history)-1].turn = t
+	g.emit(Event{Type: EventTurn, Player: t.Player, Turn: &t})
+	if len(t.Captured) > 0 {
+		g.emit(Event{Type: EventCapture, Player: t.Player, Cards: t.Captured})
+	}
+	if t.Sweep {
+		g.emit(Event{Type: EventSweep, Player: t.Player})
+	}
+	if g.notify != nil {
+		g.notify.noteTurn(t)
+	}
+}
+
+func (g *Game) noteDeal(player int, cards []card.Card) {
+	g.emit(Event{Type: EventHand, Player: player, Cards: cards})
+	if g.notify != nil {
+		g.notify.noteDeal(player, cards)
+	}
+}
+
+func (g *Game) noteEndHand() {
+	if g.notify != nil {
+		g.notify.noteEndHand()
+	}
+}
+
+// noteClear records the cards left on the table at the end of the game and
+// emits the final clear and the score.
+func (g *Game) noteClear(player int, cards []card.Card) {
+	g.clear = cards
+	if len(cards) > 0 {
+		g.emit(Event{Type: EventClear, Player: player, Cards: cards})
+	}
+	g.emit(Event{Type: EventScore, Score: g.state.breakdown()})
+	if g.notify != nil {
+		g.notify.noteClear(player, cards)
+	}
+}
@@ -0,0 +1,261 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+)
+
+func TestGameMatchesPlayGame(t *testing.T) {
+	for _, test := range stateTests {
+		rng := rand.New(rand.NewSource(test.opts.Seed))
+		players := make([]Player, test.players)
+		for i := range players {
+			players[i] = &wanderer{rng: rng, rules: test.opts.Rules}
+		}
+		want, err := PlayGame(test.opts, players...)
+		if err != nil {
+			t.Fatalf("PlayGame(%+v): %v", test.opts, err)
+		}
+
+		g, err := NewGame(test.opts, test.players)
+		if err != nil {
+			t.Fatalf("NewGame(%+v): %v", test.opts, err)
+		}
+		for _, e := range want.Log {
+			if e.Type != EventTurn {
+				continue
+			}
+			if err := g.Submit(e.Player, e.Turn.Action); err != nil {
+				t.Fatalf("%+v: Submit: %v", test.opts, err)
+			}
+		}
+		if !g.Done() || g.ToMove() != -1 {
+			t.Errorf("%+v: got Done %v and ToMove %v after the last turn", test.opts, g.Done(), g.ToMove())
+		}
+		got, ok := g.Result()
+		if !ok {
+			t.Fatalf("%+v: Result not ready", test.opts)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%+v: got result %+v, expected %+v", test.opts, got, want)
+		}
+		if events := g.Events(); !reflect.DeepEqual(events, want.Log) {
+			t.Errorf("%+v: Events do not match the log of PlayGame", test.opts)
+		}
+	}
+}
+
+func TestGameSubmit(t *testing.T) {
+	g, err := NewGame(Options{Seed: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Result(); ok {
+		t.Errorf("Result: got ok before the game is over")
+	}
+	if g.ToMove() != 1 {
+		t.Fatalf("ToMove: got %v, expected 1", g.ToMove())
+	}
+	hand := g.State().Hand(1)
+	if err := g.Submit(0, Action{Card: hand[0]}); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("Submit(out of turn): got %v, expected %v", err, ErrNotYourTurn)
+	}
+	if err := g.Submit(1, Action{Card: g.State().Hand(0)[0]}); !errors.Is(err, ErrNotInHand) {
+		t.Errorf("Submit(opponent's card): got %v, expected %v", err, ErrNotInHand)
+	}
+	if n := len(g.Events()); n != 3 {
+		t.Errorf("Events: got %v events after rejected Actions, expected 3", n)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for !g.Done() {
+		actions := g.Legal()
+		if err := g.Submit(g.ToMove(), actions[rng.Intn(len(actions))]); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	if err := g.Submit(0, Action{Card: hand[0]}); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Submit(after the game): got %v, expected %v", err, ErrGameOver)
+	}
+	if e := g.Events(); e[len(e)-1].Type != EventScore {
+		t.Errorf("Events: got last event %v, expected %v", e[len(e)-1].Type, EventScore)
+	}
+}
+
+func TestObservation(t *testing.T) {
+	g, err := NewGame(Options{Seed: 1, Rules: Rules{Draw: true}}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for !g.Done() {
+		s := g.State()
+		var seen card.Set
+		for player := 0; player < 3; player++ {
+			o, err := g.Observation(player)
+			if err != nil {
+				t.Fatalf("Observation(%v): %v", player, err)
+			}
+			if o.Player != player || o.ToMove != s.Turn() {
+				t.Fatalf("Observation(%v): got player %v to move %v, expected %v", player, o.Player, o.ToMove, s.Turn())
+			}
+			if !reflect.DeepEqual(o.Hand, s.Hand(player)) {
+				t.Errorf("Observation(%v): got hand %v, expected %v", player, o.Hand, s.Hand(player))
+			}
+			if o.HandSizes[player] != len(o.Hand) {
+				t.Errorf("Observation(%v): got hand size %v, expected %v", player, o.HandSizes[player], len(o.Hand))
+			}
+			if player == s.Turn() {
+				if err := Validate(o.Position(), player, g.Legal()[0]); err != nil {
+					t.Errorf("Observation(%v): Position rejects a legal Action: %v", player, err)
+				}
+			}
+			seen = seen.Union(card.NewSet(o.Hand...))
+		}
+		o, _ := g.Observation(0)
+		n := seen.Count() + o.Deck
+		for i := range o.Keeps {
+			n += len(o.Keeps[i])
+		}
+		for _, p := range o.Piles {
+			n += len(p.Cards)
+		}
+		if n != 52 {
+			t.Fatalf("Observation: accounts for %v cards, expected 52", n)
+		}
+		actions := g.Legal()
+		if err := g.Submit(g.ToMove(), actions[rng.Intn(len(actions))]); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	for _, player := range []int{-1, 3} {
+		if _, err := g.Observation(player); err == nil {
+			t.Errorf("Observation(%v): got nil error", player)
+		}
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	for _, test := range stateTests {
+		g, err := NewGame(test.opts, test.players)
+		if err != nil {
+			t.Fatalf("NewGame(%+v): %v", test.opts, err)
+		}
+		if err := g.Undo(); !errors.Is(err, ErrNoUndo) {
+			t.Errorf("%+v: Undo at the start: got %v, expected %v", test.opts, err, ErrNoUndo)
+		}
+		rng := rand.New(rand.NewSource(test.opts.Seed))
+		snaps := []Snapshot{g.State().Snapshot()}
+		events := []int{len(g.Events())}
+		for !g.Done() {
+			actions := g.Legal()
+			if err := g.Submit(g.ToMove(), actions[rng.Intn(len(actions))]); err != nil {
+				t.Fatalf("%+v: Submit: %v", test.opts, err)
+			}
+			snaps = append(snaps, g.State().Snapshot())
+			events = append(events, len(g.Events()))
+		}
+		final, _ := g.Result()
+		if n := len(g.History()); n != len(snaps)-1 {
+			t.Errorf("%+v: got %v moves in History, expected %v", test.opts, n, len(snaps)-1)
+		}
+
+		for i := len(snaps) - 2; i >= 0; i-- {
+			if err := g.Undo(); err != nil {
+				t.Fatalf("%+v: Undo: %v", test.opts, err)
+			}
+			if got := g.State().Snapshot(); !reflect.DeepEqual(got, snaps[i]) {
+				t.Fatalf("%+v: Undo to move %v: got %+v, expected %+v", test.opts, i, got, snaps[i])
+			}
+			if n := len(g.Events()); n != events[i] {
+				t.Fatalf("%+v: Undo to move %v: got %v events, expected %v", test.opts, i, n, events[i])
+			}
+		}
+		if err := g.Undo(); !errors.Is(err, ErrNoUndo) {
+			t.Errorf("%+v: Undo past the start: got %v, expected %v", test.opts, err, ErrNoUndo)
+		}
+
+		for i := 1; i < len(snaps); i++ {
+			if err := g.Redo(); err != nil {
+				t.Fatalf("%+v: Redo: %v", test.opts, err)
+			}
+			if got := g.State().Snapshot(); !reflect.DeepEqual(got, snaps[i]) {
+				t.Fatalf("%+v: Redo to move %v: got %+v, expected %+v", test.opts, i, got, snaps[i])
+			}
+		}
+		if err := g.Redo(); !errors.Is(err, ErrNoRedo) {
+			t.Errorf("%+v: Redo past the end: got %v, expected %v", test.opts, err, ErrNoRedo)
+		}
+		if r, ok := g.Result(); !ok || !reflect.DeepEqual(r, final) {
+			t.Errorf("%+v: Redo to the end: got result %+v, expected %+v", test.opts, r, final)
+		}
+	}
+}
+
+func TestUndoSubmit(t *testing.T) {
+	g, err := NewGame(Options{Seed: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := g.Submit(g.ToMove(), g.Legal()[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Undo()
+	g.Undo()
+	actions := g.Legal()
+	if err := g.Submit(g.ToMove(), actions[len(actions)-1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Redo(); !errors.Is(err, ErrNoRedo) {
+		t.Errorf("Redo after Submit: got %v, expected %v", err, ErrNoRedo)
+	}
+	if n := len(g.History()); n != 2 {
+		t.Errorf("History: got %v moves, expected 2", n)
+	}
+}
+
+func TestBranch(t *testing.T) {
+	g, err := NewGame(Options{Seed: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for !g.Done() {
+		actions := g.Legal()
+		if err := g.Submit(g.ToMove(), actions[rng.Intn(len(actions))]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, _ := g.Result()
+	history := g.History()
+
+	for _, n := range []int{0, 5, len(history)} {
+		b, err := g.Branch(n)
+		if err != nil {
+			t.Fatalf("Branch(%v): %v", n, err)
+		}
+		if got := b.History(); !reflect.DeepEqual(got, history[:n]) {
+			t.Errorf("Branch(%v): got History %+v, expected %+v", n, got, history[:n])
+		}
+		for !b.Done() {
+			actions := b.Legal()
+			if err := b.Submit(b.ToMove(), actions[rng.Intn(len(actions))]); err != nil {
+				t.Fatalf("Branch(%v): Submit: %v", n, err)
+			}
+		}
+		for b.Undo() == nil {
+		}
+		if got, _ := g.Result(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Branch(%v): playing the branch changed the original", n)
+		}
+	}
+	if _, err := g.Branch(len(history) + 1); err == nil {
+		t.Errorf("Branch(%v): got nil, expected error", len(history)+1)
+	}
+}
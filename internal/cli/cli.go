@@ -0,0 +1,17 @@
+// Package cli holds command-line setup shared by the cassino commands.
+package cli
+
+import (
+	"flag"
+
+	"github.com/dkmccandless/cassino/game"
+)
+
+// RuleFlags defines flags in fs that select the Rules stored in r.
+func RuleFlags(fs *flag.FlagSet, r *game.Rules) {
+	fs.BoolVar(&r.Royal, "royal", r.Royal, "play Royal Cassino")
+	fs.BoolVar(&r.SpadeCassino, "spade", r.SpadeCassino, "play Spade Cassino")
+	fs.BoolVar(&r.Draw, "draw", r.Draw, "play Draw Cassino")
+	fs.BoolVar(&r.NoSweeps, "nosweeps", r.NoSweeps, "award no points for sweeps")
+	fs.BoolVar(&r.TrailWhileBuilding, "trail", r.TrailWhileBuilding, "allow trailing while controlling a build")
+}
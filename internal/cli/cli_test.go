@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/dkmccandless/cassino/game"
+)
+
+func TestRuleFlags(t *testing.T) {
+	var r game.Rules
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RuleFlags(fs, &r)
+	if err := fs.Parse([]string{"-royal", "-draw", "-trail"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (game.Rules{Royal: true, Draw: true, TrailWhileBuilding: true}); r != want {
+		t.Errorf("got %+v, expected %+v", r, want)
+	}
+}
@@ -0,0 +1,196 @@
+// Package netplay hosts games of Cassino between Players on different
+// machines.
+//
+// A Server seats each pair of clients that connect to it at a table and
+// plays a two-player game between them. Client and server exchange
+// Messages, one JSON object per line. The messages mirror the methods of
+// game.Player and its optional interfaces:
+//
+//	server → client
+//	  {"type":"init","init":{"pos":0,"dealer":1,"players":2,"rules":{},"piles":{...}}}
+//	  {"type":"hand","cards":["♠2","♥T","♦A","♣K"]}
+//	  {"type":"note","turn":{...}}
+//	  {"type":"play","piles":{...}}
+//	  {"type":"reject","action":{...},"error":"..."}
+//	  {"type":"endhand","deck":32}
+//	  {"type":"result","result":{...}}
+//	  {"type":"result","forfeit":1,"error":"..."}
+//	client → server
+//	  {"type":"action","action":{"card":"♥7","sets":[[3]]}}
+//
+// A note describes each Turn, including the client's own, as
+// game.TurnNoter.NoteTurn does. The client answers each play with an
+// action, which the server validates with the game engine. An invalid action
+// is rejected and the client is asked to play again, up to the server's
+// limit; then, or if the client disconnects or takes too long, the client
+// forfeits and the game ends with a result naming the forfeiting player. A
+// client that hangs up before it is seated is dropped without forfeiting.
+// Cards are written as by card.Card.MarshalText, and Actions and Piles use
+// the JSON encodings of package game.
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// Message types.
+const (
+	TypeInit    = "init"
+	TypeHand    = "hand"
+	TypeNote    = "note"
+	TypePlay    = "play"
+	TypeAction  = "action"
+	TypeReject  = "reject"
+	TypeEndHand = "endhand"
+	TypeResult  = "result"
+)
+
+// A Message is a line of the protocol.
+type Message struct {
+	// Type is the kind of Message. It determines which other fields are
+	// set.
+	Type string `json:"type"`
+
+	// Init describes the game to a client in an init Message.
+	Init *Init `json:"init,omitempty"`
+
+	// Cards lists the cards dealt in a hand Message.
+	Cards []card.Card `json:"cards,omitempty"`
+
+	// Turn describes a Turn in a note Message.
+	Turn *game.Turn `json:"turn,omitempty"`
+
+	// Piles contains the cards on the table in a play Message.
+	Piles map[int]game.Pile `json:"piles,omitempty"`
+
+	// Action is the Action taken in an action Message, or the Action
+	// rejected in a reject Message.
+	Action *game.Action `json:"action,omitempty"`
+
+	// Deck is the number of cards left in the deck in an endhand Message.
+	Deck int `json:"deck,omitempty"`
+
+	// Result is the result of a game that was played to the end, in a
+	// result Message.
+	Result *game.Result `json:"result,omitempty"`
+
+	// Forfeit is the player who forfeited the game, in a result Message.
+	Forfeit *int `json:"forfeit,omitempty"`
+
+	// Error describes why an Action was rejected or a player forfeited.
+	Error string `json:"error,omitempty"`
+}
+
+// Init describes a game to a client.
+type Init struct {
+	// Pos is the client's position.
+	Pos int `json:"pos"`
+
+	// Dealer is the dealer's position.
+	Dealer int `json:"dealer"`
+
+	// Players is the number of players.
+	Players int `json:"players"`
+
+	// Rules are the rules of the game.
+	Rules game.Rules `json:"rules"`
+
+	// Piles contains the cards on the table.
+	Piles map[int]game.Pile `json:"piles"`
+}
+
+// ErrForfeit is wrapped by the error Join returns when a player forfeits.
+var ErrForfeit = errors.New("forfeit")
+
+// Join plays a game as p on the server at the other end of rw and returns
+// its result. If a player forfeits, Join returns an error wrapping
+// ErrForfeit. p may implement game.TurnNoter, game.HandEnder,
+// game.GameEnder, and game.Rejecter.
+func Join(rw io.ReadWriter, p game.Player) (game.Result, error) {
+	return JoinFunc(rw, func(Init) game.Player { return p })
+}
+
+// JoinFunc is like Join, but calls newPlayer with the server's init Message
+// to create the Player, so that the Player can be configured by the game's
+// Rules and number of players.
+func JoinFunc(rw io.ReadWriter, newPlayer func(Init) game.Player) (game.Result, error) {
+	var (
+		dec  = json.NewDecoder(bufio.NewReader(rw))
+		enc  = json.NewEncoder(rw)
+		p    game.Player
+		pos  int
+		last game.Action
+	)
+	for {
+		var m Message
+		if err := dec.Decode(&m); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return game.Result{}, err
+		}
+		if (p == nil) != (m.Type == TypeInit) {
+			return game.Result{}, fmt.Errorf("unexpected %v message", m.Type)
+		}
+		switch m.Type {
+		case TypeInit:
+			if m.Init == nil {
+				return game.Result{}, fmt.Errorf("%v message without init", m.Type)
+			}
+			p, pos = newPlayer(*m.Init), m.Init.Pos
+			p.Init(pos, m.Init.Dealer, table(m.Init.Piles))
+		case TypeHand:
+			p.Hand(m.Cards)
+		case TypeNote:
+			if m.Turn == nil {
+				return game.Result{}, fmt.Errorf("%v message without turn", m.Type)
+			}
+			if tn, ok := p.(game.TurnNoter); ok {
+				tn.NoteTurn(*m.Turn)
+			} else if m.Turn.Player != pos {
+				p.Note(m.Turn.Action.Card, m.Turn.Captured)
+			}
+		case TypePlay:
+			last = p.Play(table(m.Piles))
+			if err := enc.Encode(Message{Type: TypeAction, Action: &last}); err != nil {
+				return game.Result{}, err
+			}
+		case TypeReject:
+			if r, ok := p.(game.Rejecter); ok {
+				r.Reject(last, errors.New(m.Error))
+			}
+		case TypeEndHand:
+			if he, ok := p.(game.HandEnder); ok {
+				he.EndHand(m.Deck)
+			}
+		case TypeResult:
+			if m.Forfeit != nil {
+				return game.Result{}, fmt.Errorf("%w by player %v: %v", ErrForfeit, *m.Forfeit, m.Error)
+			}
+			if m.Result == nil {
+				return game.Result{}, fmt.Errorf("%v message without result", m.Type)
+			}
+			if ge, ok := p.(game.GameEnder); ok {
+				ge.End(*m.Result)
+			}
+			return *m.Result, nil
+		default:
+			return game.Result{}, fmt.Errorf("unknown message type %q", m.Type)
+		}
+	}
+}
+
+// table returns piles, or an empty table if piles is nil.
+func table(piles map[int]game.Pile) map[int]game.Pile {
+	if piles == nil {
+		return make(map[int]game.Pile)
+	}
+	return piles
+}
@@ -0,0 +1,265 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/strategy"
+)
+
+// A testServer is a Server listening on a loopback address.
+type testServer struct {
+	addr string
+
+	// logs receives the lines the Server logs.
+	logs chan string
+
+	// stop stops the Server and waits for its games to end.
+	stop func()
+}
+
+// serve starts s on a loopback address.
+func serve(t *testing.T, s *Server) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := &testServer{addr: ln.Addr().String(), logs: make(chan string, 100)}
+	s.Logf = func(format string, args ...interface{}) {
+		ts.logs <- fmt.Sprintf(format, args...)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.Serve(ln)
+		close(done)
+	}()
+	ts.stop = func() {
+		ln.Close()
+		<-done
+	}
+	return ts
+}
+
+// expect waits for the Server to log a line containing substr.
+func (ts *testServer) expect(t *testing.T, substr string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-ts.logs:
+			if strings.Contains(line, substr) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for the server to log %q", substr)
+		}
+	}
+}
+
+// dial connects to the Server.
+func (ts *testServer) dial(t *testing.T) net.Conn {
+	conn, err := net.Dial("tcp", ts.addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// waiting waits for the Server to report that a client is waiting for an
+// opponent, so that the next client to connect is seated in position 1.
+func (ts *testServer) waiting(t *testing.T) { ts.expect(t, "waiting for an opponent") }
+
+type outcome struct {
+	r   game.Result
+	err error
+}
+
+// join connects to the Server and plays a game as the Player newPlayer
+// returns, sending the outcome on the returned channel.
+func (ts *testServer) join(t *testing.T, newPlayer func(Init) game.Player) <-chan outcome {
+	conn := ts.dial(t)
+	ch := make(chan outcome, 1)
+	go func() {
+		defer conn.Close()
+		r, err := JoinFunc(conn, newPlayer)
+		ch <- outcome{r, err}
+	}()
+	return ch
+}
+
+// seat plays a game between p0 and p1, which take positions 0 and 1.
+func (ts *testServer) seat(t *testing.T, p0, p1 game.Player) (outcome, outcome) {
+	ch0 := ts.join(t, player(p0))
+	ts.waiting(t)
+	ch1 := ts.join(t, player(p1))
+	return <-ch0, <-ch1
+}
+
+// player returns a function that returns p.
+func player(p game.Player) func(Init) game.Player {
+	return func(Init) game.Player { return p }
+}
+
+// forfeited reports whether err reports a forfeit by player.
+func forfeited(err error, player int) bool {
+	return errors.Is(err, ErrForfeit) && strings.Contains(err.Error(), fmt.Sprintf("player %v", player))
+}
+
+func TestServer(t *testing.T) {
+	for _, rules := range []game.Rules{{}, {Royal: true, Draw: true}} {
+		opts := game.Options{Seed: 1, Rules: rules}
+		ts := serve(t, &Server{Options: opts})
+		ch0 := ts.join(t, player(strategy.NewHeuristic(rules)))
+		ts.waiting(t)
+		// Player 1 learns the rules from the server.
+		var init Init
+		ch1 := ts.join(t, func(in Init) game.Player {
+			init = in
+			return strategy.NewGreedy(in.Rules)
+		})
+		o0, o1 := <-ch0, <-ch1
+		ts.stop()
+		if o0.err != nil || o1.err != nil {
+			t.Fatalf("%+v: Join: %v, %v", rules, o0.err, o1.err)
+		}
+		if init.Pos != 1 || init.Players != 2 || init.Rules != rules {
+			t.Errorf("%+v: got %+v", rules, init)
+		}
+
+		want, err := game.PlayGame(opts, strategy.NewHeuristic(rules), strategy.NewGreedy(rules))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, o := range []outcome{o0, o1} {
+			if !reflect.DeepEqual(o.r.Score, want.Score) || !reflect.DeepEqual(o.r.Keep, want.Keep) {
+				t.Errorf("%+v: player %v got score %v, expected %v", rules, i, o.r.Score, want.Score)
+			}
+			if len(o.r.Log) != len(want.Log) {
+				t.Errorf("%+v: player %v got %v events, expected %v", rules, i, len(o.r.Log), len(want.Log))
+			}
+		}
+	}
+}
+
+// A script is a client that takes its Actions from a list, and then plays as
+// Greedy does.
+type script struct {
+	strategy.Greedy
+	actions  []game.Action
+	rejected []string
+}
+
+func (s *script) Play(piles map[int]game.Pile) game.Action {
+	if len(s.actions) == 0 {
+		return s.Greedy.Play(piles)
+	}
+	a := s.actions[0]
+	s.actions = s.actions[1:]
+	return a
+}
+
+func (s *script) Reject(a game.Action, err error) { s.rejected = append(s.rejected, err.Error()) }
+
+func TestServerReject(t *testing.T) {
+	ts := serve(t, &Server{Options: game.Options{Seed: 1}, Retries: 1})
+	defer ts.stop()
+
+	// Player 1 moves first. Its second invalid Action forfeits the game.
+	bad := game.Action{Card: 51, Sets: [][]int{{99}}}
+	p1 := &script{Greedy: *strategy.NewGreedy(game.Rules{}), actions: []game.Action{bad, bad}}
+	o0, o1 := ts.seat(t, strategy.NewGreedy(game.Rules{}), p1)
+	for i, o := range []outcome{o0, o1} {
+		if !forfeited(o.err, 1) {
+			t.Errorf("player %v: got %v, expected forfeit by player 1", i, o.err)
+		}
+	}
+	if len(p1.rejected) != 1 {
+		t.Errorf("got %v rejections, expected 1: %q", len(p1.rejected), p1.rejected)
+	}
+}
+
+func TestServerDisconnect(t *testing.T) {
+	ts := serve(t, &Server{Options: game.Options{Seed: 1}})
+	defer ts.stop()
+
+	ch := ts.join(t, player(strategy.NewGreedy(game.Rules{})))
+	ts.waiting(t)
+
+	// Player 1 reads its first message and hangs up.
+	conn := ts.dial(t)
+	var m Message
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&m); err != nil || m.Type != TypeInit {
+		t.Errorf("first message: got %+v, %v, expected %v", m, err, TypeInit)
+	}
+	conn.Close()
+
+	if o := <-ch; !forfeited(o.err, 1) {
+		t.Errorf("got %v, expected forfeit by player 1", o.err)
+	}
+}
+
+func TestServerWaitingDisconnect(t *testing.T) {
+	ts := serve(t, &Server{Options: game.Options{Seed: 1}})
+	defer ts.stop()
+
+	// A client that hangs up before it is seated is dropped.
+	conn := ts.dial(t)
+	ts.waiting(t)
+	conn.Close()
+	ts.expect(t, conn.LocalAddr().String()+" left while waiting")
+
+	o0, o1 := ts.seat(t, strategy.NewGreedy(game.Rules{}), strategy.NewHeuristic(game.Rules{}))
+	if o0.err != nil || o1.err != nil {
+		t.Errorf("Join: %v, %v", o0.err, o1.err)
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	ts := serve(t, &Server{Options: game.Options{Seed: 1}, Timeout: 100 * time.Millisecond})
+	defer ts.stop()
+
+	ch := ts.join(t, player(strategy.NewGreedy(game.Rules{})))
+	ts.waiting(t)
+
+	// Player 1 connects but never answers.
+	conn := ts.dial(t)
+	defer conn.Close()
+
+	if o := <-ch; !forfeited(o.err, 1) {
+		t.Errorf("got %v, expected forfeit by player 1", o.err)
+	}
+}
+
+func TestJoinUnexpectedEOF(t *testing.T) {
+	server, client := net.Pipe()
+	go func() {
+		json.NewEncoder(server).Encode(Message{Type: TypeInit, Init: &Init{Players: 2}})
+		server.Close()
+	}()
+	if _, err := Join(client, strategy.NewGreedy(game.Rules{})); err == nil {
+		t.Errorf("Join: got nil, expected error")
+	}
+}
+
+func TestJoinBeforeInit(t *testing.T) {
+	server, client := net.Pipe()
+	go func() {
+		json.NewEncoder(server).Encode(Message{Type: TypePlay})
+		server.Close()
+	}()
+	called := false
+	_, err := JoinFunc(client, func(Init) game.Player {
+		called = true
+		return strategy.NewGreedy(game.Rules{})
+	})
+	if err == nil || called {
+		t.Errorf("JoinFunc: got %v, called %v; expected error before init", err, called)
+	}
+}
@@ -0,0 +1,255 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// A Server seats clients in pairs and plays a game between each pair.
+type Server struct {
+	// Options configures each game. Its Policy and Retries are ignored. If
+	// Seed is nonzero, the game at the nth table is dealt with Seed+n.
+	Options game.Options
+
+	// Retries is the number of further Actions a client may attempt after
+	// an invalid Action before forfeiting.
+	Retries int
+
+	// Timeout, if nonzero, limits the time a client may take to answer a
+	// play Message.
+	Timeout time.Duration
+
+	// Logf, if not nil, is called to log clients waiting for an opponent
+	// and the outcome of each game.
+	Logf func(format string, args ...interface{})
+
+	// wg tracks the games in progress.
+	wg sync.WaitGroup
+}
+
+// Serve accepts connections on ln and seats each pair of clients at a table,
+// until Accept fails. A client that hangs up while waiting for an opponent
+// is dropped. Serve then waits for the games in progress to end and returns
+// the error from Accept.
+func (s *Server) Serve(ln net.Listener) error {
+	defer s.wg.Wait()
+	conns := make(chan net.Conn)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				errc <- err
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	var w *waiter
+	for n := int64(0); ; {
+		var hangup <-chan error
+		if w != nil {
+			hangup = w.done
+		}
+		select {
+		case err := <-errc:
+			if w != nil {
+				w.conn.Close()
+			}
+			return err
+		case err := <-hangup:
+			s.logf("%v left while waiting: %v", w.conn.RemoteAddr(), err)
+			w.conn.Close()
+			w = nil
+		case conn := <-conns:
+			if w == nil {
+				w = watch(conn)
+				s.logf("%v waiting for an opponent", conn.RemoteAddr())
+				continue
+			}
+			if err := w.stop(); err != nil {
+				s.logf("%v left while waiting: %v", w.conn.RemoteAddr(), err)
+				w.conn.Close()
+				w = watch(conn)
+				s.logf("%v waiting for an opponent", conn.RemoteAddr())
+				continue
+			}
+			opts := s.Options
+			if opts.Seed != 0 {
+				opts.Seed += n
+			}
+			n++
+			s.wg.Add(1)
+			go func(conns [2]net.Conn) {
+				defer s.wg.Done()
+				s.play(opts, conns)
+			}([2]net.Conn{w.conn, conn})
+			w = nil
+		}
+	}
+}
+
+// A waiter watches the connection of a client waiting for an opponent, so
+// that the client can be dropped if it hangs up. The client sends nothing
+// before it is seated, so any result of reading from the connection means
+// that it should be dropped.
+type waiter struct {
+	conn net.Conn
+
+	// done receives the result of reading from conn.
+	done chan error
+}
+
+// watch begins watching conn.
+func watch(conn net.Conn) *waiter {
+	w := &waiter{conn: conn, done: make(chan error, 1)}
+	go func() {
+		var b [1]byte
+		_, err := conn.Read(b[:])
+		if err == nil {
+			err = errors.New("message before init")
+		}
+		w.done <- err
+	}()
+	return w
+}
+
+// stop stops watching the connection. It returns nil if the client may be
+// seated, or the error that ended the watch otherwise.
+func (w *waiter) stop() error {
+	w.conn.SetReadDeadline(time.Now())
+	err := <-w.done
+	w.conn.SetReadDeadline(time.Time{})
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+// logf calls Logf if it is not nil.
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
+
+// play plays a game between two clients and sends them its outcome.
+func (s *Server) play(opts game.Options, conns [2]net.Conn) {
+	opts.Policy, opts.Retries = game.Retry, s.Retries
+	var players [2]*remote
+	for i, conn := range conns {
+		defer conn.Close()
+		players[i] = &remote{
+			conn:    conn,
+			dec:     json.NewDecoder(bufio.NewReader(conn)),
+			enc:     json.NewEncoder(conn),
+			rules:   opts.Rules,
+			timeout: s.Timeout,
+		}
+	}
+	r, err := game.PlayGame(opts, players[0], players[1])
+	m := Message{Type: TypeResult, Result: &r}
+	if err != nil {
+		m = Message{Type: TypeResult, Error: err.Error()}
+		var ae *game.ActionError
+		if errors.As(err, &ae) {
+			m.Forfeit = &ae.Player
+			if perr := players[ae.Player].err; perr != nil {
+				m.Error = fmt.Sprintf("player %v: %v", ae.Player, perr)
+			}
+		}
+	}
+	for _, p := range players {
+		p.send(m)
+	}
+	addrs := fmt.Sprintf("%v vs %v", conns[0].RemoteAddr(), conns[1].RemoteAddr())
+	switch {
+	case m.Forfeit != nil:
+		s.logf("%v: player %v forfeits: %v", addrs, *m.Forfeit, m.Error)
+	case err != nil:
+		s.logf("%v: %v", addrs, err)
+	default:
+		s.logf("%v: score %v (seed %v)", addrs, r.Score, r.Seed)
+	}
+}
+
+// A remote is a Player that relays the game to a client and takes its
+// Actions from it. After an I/O error, it sends nothing further and forfeits
+// on its next turn.
+type remote struct {
+	conn    net.Conn
+	dec     *json.Decoder
+	enc     *json.Encoder
+	rules   game.Rules
+	timeout time.Duration
+
+	// pos is the Player's position.
+	pos int
+
+	// err records the first error in communicating with the client.
+	err error
+}
+
+func (r *remote) Init(pos, dealer int, piles map[int]game.Pile) {
+	r.pos = pos
+	r.send(Message{Type: TypeInit, Init: &Init{
+		Pos:     pos,
+		Dealer:  dealer,
+		Players: 2,
+		Rules:   r.rules,
+		Piles:   piles,
+	}})
+}
+
+func (r *remote) Hand(hand []card.Card) { r.send(Message{Type: TypeHand, Cards: hand}) }
+
+func (r *remote) Note(played card.Card, captured []card.Card) {}
+
+func (r *remote) NoteTurn(t game.Turn) { r.send(Message{Type: TypeNote, Turn: &t}) }
+
+func (r *remote) EndHand(deck int) { r.send(Message{Type: TypeEndHand, Deck: deck}) }
+
+func (r *remote) Reject(a game.Action, err error) {
+	r.send(Message{Type: TypeReject, Action: &a, Error: err.Error()})
+}
+
+func (r *remote) Play(piles map[int]game.Pile) game.Action {
+	r.send(Message{Type: TypePlay, Piles: piles})
+	if r.err != nil {
+		return game.Action{Card: -1}
+	}
+	if r.timeout != 0 {
+		r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+		defer r.conn.SetReadDeadline(time.Time{})
+	}
+	var m Message
+	switch err := r.dec.Decode(&m); {
+	case err != nil:
+		r.err = err
+	case m.Type != TypeAction || m.Action == nil:
+		r.err = fmt.Errorf("got %q message, expected %q", m.Type, TypeAction)
+	default:
+		return *m.Action
+	}
+	return game.Action{Card: -1}
+}
+
+// send sends a Message to the client, unless communication has failed.
+func (r *remote) send(m Message) {
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(m); err != nil {
+		r.err = err
+	}
+}
@@ -0,0 +1,33 @@
+package strategy
+
+import "github.com/dkmccandless/cassino/game"
+
+// Greedy is a Player that captures as many cards as it can. If it cannot
+// capture, it makes the largest build it can, and otherwise trails its
+// lowest card.
+type Greedy struct {
+	base
+}
+
+// NewGreedy returns a Greedy Player for a game played under rules.
+func NewGreedy(rules game.Rules) *Greedy {
+	return &Greedy{base{rules: rules}}
+}
+
+func (g *Greedy) Play(piles map[int]game.Pile) game.Action {
+	actions := g.legal(piles)
+	best, bestN := actions[0], -1
+	for _, a := range actions {
+		// Rank captures above builds above trails.
+		n := 0
+		if c := captured(a, piles); c != nil {
+			n = 100 + len(c)
+		} else if b := built(a, piles); b != nil {
+			n = len(b)
+		}
+		if n > bestN {
+			best, bestN = a, n
+		}
+	}
+	return g.play(best)
+}
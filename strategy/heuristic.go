@@ -0,0 +1,79 @@
+package strategy
+
+import (
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// Heuristic is a Player that values each Action by the points its cards are
+// worth: Big and Little Cassino, aces, spades, and each card's share of the
+// points for most cards. It favors captures and sweeps, secures valuable
+// cards in builds it can capture later, and trails the cards least useful to
+// its opponents.
+type Heuristic struct {
+	base
+}
+
+// NewHeuristic returns a Heuristic Player for a game played under rules.
+func NewHeuristic(rules game.Rules) *Heuristic {
+	return &Heuristic{base{rules: rules}}
+}
+
+func (h *Heuristic) Play(piles map[int]game.Pile) game.Action {
+	actions := h.legal(piles)
+	best, bestV := actions[0], 0.0
+	for i, a := range actions {
+		if v := evaluate(h.rules, a, piles); i == 0 || v > bestV {
+			best, bestV = a, v
+		}
+	}
+	return h.play(best)
+}
+
+// evaluate estimates the value of an Action.
+func evaluate(rules game.Rules, a game.Action, piles map[int]game.Pile) float64 {
+	if c := captured(a, piles); c != nil {
+		v := worth(rules, a.Card)
+		for _, c := range c {
+			v += worth(rules, c)
+		}
+		if isSweep(a, piles) && !rules.NoSweeps {
+			v++
+		}
+		return v
+	}
+	if b := built(a, piles); b != nil {
+		// A build is likely, but not certain, to be captured later.
+		var v float64
+		for _, c := range b {
+			v += worth(rules, c)
+		}
+		return v / 2
+	}
+	// Trailing gives a card away to the opponents.
+	return -worth(rules, a.Card) - 0.05*float64(a.Card.Rank())
+}
+
+// worth estimates the points a card is worth to the player who captures it.
+func worth(rules game.Rules, c card.Card) float64 {
+	// Most cards: 3 points for at least 27 of 52 cards
+	v := 3.0 / 27
+	switch {
+	case c == card.BigCassino:
+		v += 2
+	case c == card.LittleCassino, c.IsAce():
+		v++
+	}
+	if c.IsSpade() {
+		switch {
+		case !rules.SpadeCassino:
+			// Most spades: 1 point for at least 7 of 13 spades
+			v += 1.0 / 7
+		case c == card.LittleCassino, c.Rank() == card.Jack:
+			v += 2
+		default:
+			v++
+		}
+	}
+	return v
+}
@@ -0,0 +1,278 @@
+package strategy
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// MonteCarloOptions configures a MonteCarlo Player.
+type MonteCarloOptions struct {
+	// Rules are the rules of the game.
+	Rules game.Rules
+
+	// Players is the number of players in the game. If zero, it is 2.
+	Players int
+
+	// Partnerships reports whether the game is played in partnerships.
+	Partnerships bool
+
+	// Rollouts is the number of deals sampled for each decision. If zero,
+	// it is 100.
+	Rollouts int
+
+	// Budget, if nonzero, limits the time spent on each decision. At least
+	// one deal is always sampled.
+	Budget time.Duration
+
+	// Source, if not nil, is used to sample deals. Otherwise a random Source
+	// is used.
+	Source rand.Source
+}
+
+// MonteCarlo is a Player that samples the cards it has not seen into its
+// opponents' hands and the deck, plays out the rest of the game from each
+// sample, and takes the Action with the best average score differential.
+// Simulated players choose their Actions as Heuristic does.
+type MonteCarlo struct {
+	base
+	opts    MonteCarloOptions
+	rng     *rand.Rand
+	tracker *Tracker
+
+	// dealer is the dealer's position.
+	dealer int
+
+	// npiles is the greatest Pile ID seen.
+	npiles int
+}
+
+// NewMonteCarlo returns a MonteCarlo Player configured by opts.
+func NewMonteCarlo(opts MonteCarloOptions) *MonteCarlo {
+	if opts.Players == 0 {
+		opts.Players = 2
+	}
+	if opts.Rollouts == 0 {
+		opts.Rollouts = 100
+	}
+	src := opts.Source
+	if src == nil {
+		src = rand.NewSource(rand.Int63())
+	}
+	return &MonteCarlo{
+		base:    base{rules: opts.Rules},
+		opts:    opts,
+		rng:     rand.New(src),
+		tracker: NewTracker(opts.Rules, opts.Players, opts.Partnerships),
+	}
+}
+
+func (m *MonteCarlo) Init(pos, dealer int, piles map[int]game.Pile) {
+	m.base.Init(pos, dealer, piles)
+	m.tracker.Init(pos, dealer, piles)
+	m.dealer, m.npiles = dealer, 0
+	for id := range piles {
+		if id > m.npiles {
+			m.npiles = id
+		}
+	}
+}
+
+func (m *MonteCarlo) Hand(hand []card.Card) {
+	m.base.Hand(hand)
+	m.tracker.Hand(hand)
+}
+
+func (m *MonteCarlo) NoteTurn(t game.Turn) {
+	m.tracker.NoteTurn(t)
+	if t.ID > m.npiles {
+		m.npiles = t.ID
+	}
+}
+
+func (m *MonteCarlo) Play(piles map[int]game.Pile) game.Action {
+	actions := m.legal(piles)
+	if len(actions) == 1 {
+		return m.play(actions[0])
+	}
+	start := time.Now()
+	totals := make([]int, len(actions))
+	for n := 0; n < m.opts.Rollouts; n++ {
+		if n > 0 && m.opts.Budget != 0 && time.Since(start) >= m.opts.Budget {
+			break
+		}
+		s, err := game.Restore(m.sample(piles))
+		if err != nil {
+			continue
+		}
+		for i, a := range actions {
+			r := s.Clone()
+			if err := r.Apply(a); err != nil {
+				continue
+			}
+			playOut(r)
+			totals[i] += m.differential(r.Score())
+		}
+	}
+	best := 0
+	for i := range actions {
+		if totals[i] > totals[best] {
+			best = i
+		}
+	}
+	return m.play(actions[best])
+}
+
+// playOut plays a simulated game to the end, choosing each Action as
+// Heuristic does.
+func playOut(s *game.State) {
+	for !s.IsTerminal() {
+		actions := s.Legal()
+		if len(actions) == 0 {
+			// A sampled hand may lack the card needed to capture the
+			// player's build. End the simulation where it stands.
+			return
+		}
+		s.Apply(rollout(s.Rules(), actions, s.Piles()))
+	}
+}
+
+// differential returns the score of the Player's side minus the greatest
+// score among the other sides.
+func (m *MonteCarlo) differential(score []int) int {
+	best, first := 0, true
+	for i, v := range score {
+		if i == m.pos || m.opts.Partnerships && i%2 == m.pos%2 {
+			continue
+		}
+		if first || v > best {
+			best, first = v, false
+		}
+	}
+	return score[m.pos] - best
+}
+
+// rollout chooses an Action for a simulated player as Heuristic does.
+func rollout(rules game.Rules, actions []game.Action, piles map[int]game.Pile) game.Action {
+	best, bestV := actions[0], 0.0
+	for i, a := range actions {
+		if v := evaluate(rules, a, piles); i == 0 || v > bestV {
+			best, bestV = a, v
+		}
+	}
+	return best
+}
+
+// sample returns a Snapshot of the current position in which the unseen cards
+// are dealt at random to the other players' hands and the deck.
+func (m *MonteCarlo) sample(piles map[int]game.Pile) game.Snapshot {
+	unseen := m.tracker.Unseen()
+	m.rng.Shuffle(len(unseen), func(i, j int) { unseen[i], unseen[j] = unseen[j], unseen[i] })
+
+	s := game.Snapshot{
+		Rules:        m.rules,
+		Partnerships: m.opts.Partnerships,
+		Dealer:       m.dealer,
+		Turn:         m.pos,
+		Hands:        make([][]card.Card, m.opts.Players),
+		Keeps:        make([][]card.Card, m.opts.Players),
+		Sweeps:       make([]int, m.opts.Players),
+		Piles:        piles,
+		NPiles:       m.npiles,
+		LastCapture:  m.tracker.LastCapture(),
+	}
+	for i := range s.Hands {
+		s.Keeps[i] = m.tracker.Keep(i)
+		s.Sweeps[i] = m.tracker.Sweeps(i)
+		if i == m.pos {
+			s.Hands[i] = append([]card.Card(nil), m.hand...)
+			continue
+		}
+		n := m.tracker.HandSize(i)
+		s.Hands[i], unseen = unseen[:n:n], unseen[n:]
+	}
+	s.Deck = unseen
+	m.satisfy(&s)
+	return s
+}
+
+// satisfy exchanges unseen cards so that each other player holds a card that
+// can capture each of their builds, as they must.
+func (m *MonteCarlo) satisfy(s *game.Snapshot) {
+	for i := range s.Hands {
+		if i == m.pos {
+			continue
+		}
+		for _, p := range s.Piles {
+			if len(p.Cards) < 2 || p.Controller != i || m.holds(s.Hands[i], p.Value) {
+				continue
+			}
+			m.supply(s, i, p.Value)
+		}
+	}
+}
+
+// supply exchanges a card of player's hand for an unseen card of value v held
+// elsewhere, keeping the cards player needs for their other builds.
+func (m *MonteCarlo) supply(s *game.Snapshot, player, v int) {
+	// Choose the card to give up.
+	give := -1
+	for k, c := range s.Hands[player] {
+		if !m.needed(s, player, c) {
+			give = k
+			break
+		}
+	}
+	if give < 0 {
+		return
+	}
+	swap := func(from []card.Card) bool {
+		for k, c := range from {
+			if m.rules.HasValue(c, v) {
+				from[k], s.Hands[player][give] = s.Hands[player][give], c
+				return true
+			}
+		}
+		return false
+	}
+	if swap(s.Deck) {
+		return
+	}
+	for i := range s.Hands {
+		if i != m.pos && i != player && swap(s.Hands[i]) {
+			return
+		}
+	}
+}
+
+// needed reports whether c is the only card in player's hand that can capture
+// one of their builds.
+func (m *MonteCarlo) needed(s *game.Snapshot, player int, c card.Card) bool {
+	for _, p := range s.Piles {
+		if len(p.Cards) < 2 || p.Controller != player || !m.rules.HasValue(c, p.Value) {
+			continue
+		}
+		var n int
+		for _, h := range s.Hands[player] {
+			if m.rules.HasValue(h, p.Value) {
+				n++
+			}
+		}
+		if n == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// holds reports whether hand contains a card that may take the value v.
+func (m *MonteCarlo) holds(hand []card.Card, v int) bool {
+	for _, c := range hand {
+		if m.rules.HasValue(c, v) {
+			return true
+		}
+	}
+	return false
+}
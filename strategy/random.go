@@ -0,0 +1,24 @@
+package strategy
+
+import (
+	"math/rand"
+
+	"github.com/dkmccandless/cassino/game"
+)
+
+// Random is a Player that takes a valid Action chosen uniformly at random.
+type Random struct {
+	base
+	rng *rand.Rand
+}
+
+// NewRandom returns a Random Player for a game played under rules that makes
+// its choices using src.
+func NewRandom(rules game.Rules, src rand.Source) *Random {
+	return &Random{base: base{rules: rules}, rng: rand.New(src)}
+}
+
+func (r *Random) Play(piles map[int]game.Pile) game.Action {
+	actions := r.legal(piles)
+	return r.play(actions[r.rng.Intn(len(actions))])
+}
@@ -0,0 +1,117 @@
+// Package strategy provides computer Players for Cassino.
+package strategy
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// Names lists the strategies New accepts.
+var Names = []string{"random", "greedy", "heuristic", "montecarlo"}
+
+// New returns a new Player using the named strategy. opts.Rules are the rules
+// of the game; the other options configure a MonteCarlo Player, and a Random
+// Player draws from opts.Source if it is not nil.
+func New(name string, opts MonteCarloOptions) (game.Player, error) {
+	switch name {
+	case "random":
+		src := opts.Source
+		if src == nil {
+			src = rand.NewSource(rand.Int63())
+		}
+		return NewRandom(opts.Rules, src), nil
+	case "greedy":
+		return NewGreedy(opts.Rules), nil
+	case "heuristic":
+		return NewHeuristic(opts.Rules), nil
+	case "montecarlo":
+		return NewMonteCarlo(opts), nil
+	}
+	return nil, fmt.Errorf("unknown player %q", name)
+}
+
+// base tracks the state a Player needs to choose valid Actions.
+type base struct {
+	// rules are the rules of the game.
+	rules game.Rules
+
+	// pos is the Player's position.
+	pos int
+
+	// hand contains the cards in the Player's hand.
+	hand []card.Card
+}
+
+func (b *base) Init(pos, dealer int, piles map[int]game.Pile) {
+	b.pos = pos
+	b.hand = nil
+}
+
+func (b *base) Hand(hand []card.Card) { b.hand = append(b.hand, hand...) }
+
+func (b *base) Note(played card.Card, captured []card.Card) {}
+
+// legal returns the valid Actions available to the Player.
+func (b *base) legal(piles map[int]game.Pile) []game.Action {
+	return b.rules.LegalActions(b.hand, piles, b.pos)
+}
+
+// play removes the card of an Action from the Player's hand and returns the
+// Action.
+func (b *base) play(a game.Action) game.Action {
+	for i, c := range b.hand {
+		if c == a.Card {
+			b.hand = append(b.hand[:i], b.hand[i+1:]...)
+			break
+		}
+	}
+	return a
+}
+
+// captured returns the cards an Action captures from the table, or nil if it
+// is not a capture.
+func captured(a game.Action, piles map[int]game.Pile) []card.Card {
+	if len(a.Add) > 0 || a.Build {
+		return nil
+	}
+	var cards []card.Card
+	for _, set := range a.Sets {
+		for _, id := range set {
+			cards = append(cards, piles[id].Cards...)
+		}
+	}
+	return cards
+}
+
+// isSweep reports whether an Action captures every card on the table.
+func isSweep(a game.Action, piles map[int]game.Pile) bool {
+	if len(a.Add) > 0 || a.Build || len(a.Sets) == 0 {
+		return false
+	}
+	var n int
+	for _, set := range a.Sets {
+		n += len(set)
+	}
+	return n == len(piles)
+}
+
+// built returns the cards an Action gathers into a build, including the hand
+// card, or nil if it is not a build.
+func built(a game.Action, piles map[int]game.Pile) []card.Card {
+	if len(a.Add) == 0 && !a.Build {
+		return nil
+	}
+	var cards []card.Card
+	for _, set := range a.Sets {
+		for _, id := range set {
+			cards = append(cards, piles[id].Cards...)
+		}
+	}
+	for _, id := range a.Add {
+		cards = append(cards, piles[id].Cards...)
+	}
+	return append(cards, a.Card)
+}
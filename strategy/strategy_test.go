@@ -0,0 +1,118 @@
+package strategy
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dkmccandless/cassino/game"
+)
+
+func TestStrategies(t *testing.T) {
+	for name, rules := range map[string]game.Rules{
+		"basic":         {},
+		"royal":         {Royal: true},
+		"spade cassino": {SpadeCassino: true},
+		"draw":          {Draw: true},
+		"trail":         {TrailWhileBuilding: true, NoSweeps: true},
+	} {
+		for n := 2; n <= 4; n++ {
+			for seed := int64(1); seed <= 10; seed++ {
+				players := []game.Player{
+					NewRandom(rules, rand.NewSource(seed)),
+					NewGreedy(rules),
+					NewHeuristic(rules),
+					NewRandom(rules, rand.NewSource(-seed)),
+				}[:n]
+				opts := game.Options{Seed: seed, Rules: rules, Dealer: int(seed) % n}
+				if _, err := game.PlayGame(opts, players...); err != nil {
+					t.Fatalf("PlayGame(%q, %v players, seed %v): %v", name, n, seed, err)
+				}
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range Names {
+		p, err := New(name, MonteCarloOptions{Rollouts: 1, Source: rand.NewSource(1)})
+		if err != nil {
+			t.Fatalf("New(%q): %v", name, err)
+		}
+		opts := game.Options{Seed: 1}
+		if _, err := game.PlayGame(opts, p, NewGreedy(game.Rules{})); err != nil {
+			t.Errorf("PlayGame(%q): %v", name, err)
+		}
+	}
+	if _, err := New("oracle", MonteCarloOptions{}); err == nil {
+		t.Errorf("New(%q): got nil error", "oracle")
+	}
+}
+
+func TestHeuristicBeatsRandom(t *testing.T) {
+	var wins, games int
+	for seed := int64(1); seed <= 100; seed++ {
+		h, r := NewHeuristic(game.Rules{}), NewRandom(game.Rules{}, rand.NewSource(seed))
+		res, err := game.PlayGame(game.Options{Seed: seed, Dealer: int(seed) % 2}, h, r)
+		if err != nil {
+			t.Fatalf("PlayGame(seed %v): %v", seed, err)
+		}
+		games++
+		if res.Score[0] > res.Score[1] {
+			wins++
+		}
+	}
+	if wins*2 <= games {
+		t.Errorf("Heuristic won %v of %v games against Random", wins, games)
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	for name, rules := range map[string]game.Rules{
+		"basic": {},
+		"royal": {Royal: true},
+		"draw":  {Draw: true},
+	} {
+		for n := 2; n <= 4; n++ {
+			for _, partnerships := range []bool{false, true} {
+				if partnerships && n != 4 {
+					continue
+				}
+				seed := int64(n)
+				players := []game.Player{
+					NewMonteCarlo(MonteCarloOptions{
+						Rules:        rules,
+						Players:      n,
+						Partnerships: partnerships,
+						Rollouts:     2,
+						Source:       rand.NewSource(seed),
+					}),
+					NewHeuristic(rules),
+					NewGreedy(rules),
+					NewRandom(rules, rand.NewSource(seed)),
+				}[:n]
+				opts := game.Options{Seed: seed, Rules: rules, Partnerships: partnerships}
+				if _, err := game.PlayGame(opts, players...); err != nil {
+					t.Fatalf("PlayGame(%q, %v players, seed %v): %v", name, n, seed, err)
+				}
+			}
+		}
+	}
+}
+
+func TestMonteCarloBeatsHeuristic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	var diff int
+	for seed := int64(1); seed <= 20; seed++ {
+		m := NewMonteCarlo(MonteCarloOptions{Rollouts: 20, Source: rand.NewSource(seed)})
+		res, err := game.PlayGame(game.Options{Seed: seed, Dealer: int(seed) % 2}, m, NewHeuristic(game.Rules{}))
+		if err != nil {
+			t.Fatalf("PlayGame(seed %v): %v", seed, err)
+		}
+		diff += res.Score[0] - res.Score[1]
+	}
+	if diff <= 0 {
+		t.Errorf("MonteCarlo outscored Heuristic by %v over 20 games", diff)
+	}
+}
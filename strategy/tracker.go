@@ -0,0 +1,284 @@
+package strategy
+
+import (
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// A Tracker records the cards a Player has seen and the state of the game that
+// follows from them. It consumes the Player's Init, Hand, NoteTurn, and
+// EndHand calls, so a Player that embeds a Tracker is a game.TurnNoter and a
+// game.HandEnder and must forward these calls to it if it defines them itself.
+// A Player that is not a game.TurnNoter may instead forward its Note calls and
+// report its own Actions with NotePlay.
+type Tracker struct {
+	rules        game.Rules
+	players      int
+	partnerships bool
+
+	// pos is the Player's position.
+	pos int
+
+	// dealer is the dealer's position.
+	dealer int
+
+	// seen records the cards that have been seen.
+	seen card.Set
+
+	// hand contains the cards in the Player's hand.
+	hand card.Set
+
+	// hands records how many cards each player holds.
+	hands []int
+
+	// deck is the number of cards not yet dealt.
+	deck int
+
+	// keep contains the cards captured by each player.
+	keep [][]card.Card
+
+	// sweeps records how many sweeps each player has made.
+	sweeps []int
+
+	// lastCapture records who played the most recent capture.
+	lastCapture int
+
+	// turn is the player to move.
+	turn int
+
+	// table is the number of cards on the table.
+	table int
+}
+
+// NewTracker returns a Tracker for a game among players players played under
+// rules, in partnerships if partnerships is true.
+func NewTracker(rules game.Rules, players int, partnerships bool) *Tracker {
+	return &Tracker{rules: rules, players: players, partnerships: partnerships}
+}
+
+func (t *Tracker) Init(pos, dealer int, piles map[int]game.Pile) {
+	t.pos, t.dealer = pos, dealer
+	t.seen, t.hand = 0, 0
+	t.hands = make([]int, t.players)
+	t.deck = 52
+	t.keep = make([][]card.Card, t.players)
+	t.sweeps = make([]int, t.players)
+	t.lastCapture = dealer
+	t.turn = (dealer + 1) % t.players
+	t.table = 0
+	for _, p := range piles {
+		for _, c := range p.Cards {
+			t.seen = t.seen.Add(c)
+			t.deck--
+			t.table++
+		}
+	}
+}
+
+func (t *Tracker) Hand(hand []card.Card) {
+	t.hand = t.hand.Union(card.NewSet(hand...))
+	t.seen = t.seen.Union(t.hand)
+	for _, n := range t.hands {
+		if n != 0 {
+			// In Draw Cassino, the card is drawn after the Player's
+			// turn, and was counted when the turn was recorded.
+			return
+		}
+	}
+	// A new round: every player is dealt as many cards.
+	for i := range t.hands {
+		t.hands[i] += len(hand)
+		t.deck -= len(hand)
+	}
+}
+
+// Note records a turn taken by another player. Since a Tracker is a
+// game.TurnNoter, the game calls NoteTurn instead; Note serves Players that
+// forward Note calls to it, and who must also report their own Actions with
+// NotePlay.
+func (t *Tracker) Note(played card.Card, captured []card.Card) {
+	t.record(t.turn, played, captured)
+}
+
+// NotePlay records the Player's own Action a, taken on the table piles. It
+// serves Players that forward Note calls instead of NoteTurn calls.
+func (t *Tracker) NotePlay(a game.Action, piles map[int]game.Pile) {
+	var cards []card.Card
+	if c := captured(a, piles); c != nil {
+		cards = append(c, a.Card)
+	}
+	t.record(t.pos, a.Card, cards)
+}
+
+func (t *Tracker) NoteTurn(turn game.Turn) {
+	t.record(turn.Player, turn.Action.Card, turn.Captured)
+}
+
+// record records a turn in which player played a card and captured the cards
+// in captured, which include the played card if there are any.
+func (t *Tracker) record(player int, played card.Card, captured []card.Card) {
+	t.seen = t.seen.Union(card.NewSet(captured...)).Add(played)
+	if player == t.pos {
+		t.hand = t.hand.Remove(played)
+	}
+	t.hands[player]--
+	if t.rules.Draw && t.deck != 0 {
+		t.hands[player]++
+		t.deck--
+	}
+	if len(captured) == 0 {
+		t.table++
+	} else {
+		t.keep[player] = append(t.keep[player], captured...)
+		t.lastCapture = player
+		t.table -= len(captured) - 1
+		if t.table == 0 {
+			t.sweeps[player]++
+		}
+	}
+	// The next player with cards moves, or the player after the dealer
+	// at the start of the next round.
+	t.turn = (t.dealer + 1) % t.players
+	for k := 1; k <= t.players; k++ {
+		if i := (player + k) % t.players; t.hands[i] != 0 {
+			t.turn = i
+			break
+		}
+	}
+}
+
+// EndHand records the end of a round. At the end of the game, the cards left
+// on the table go to the player who captured last.
+func (t *Tracker) EndHand(deck int) {
+	t.deck = deck
+	if deck != 0 {
+		return
+	}
+	table := t.seen.Difference(t.hand)
+	for _, k := range t.keep {
+		table = table.Difference(card.NewSet(k...))
+	}
+	t.keep[t.lastCapture] = append(t.keep[t.lastCapture], table.Cards()...)
+	t.table = 0
+}
+
+// Seen reports whether c has been seen.
+func (t *Tracker) Seen(c card.Card) bool { return t.seen.Contains(c) }
+
+// Unseen returns the cards that have not been seen, in ascending order.
+// They are in the other players' hands or the deck.
+func (t *Tracker) Unseen() []card.Card { return card.All.Difference(t.seen).Cards() }
+
+// Deck returns the number of cards remaining in the deck.
+func (t *Tracker) Deck() int { return t.deck }
+
+// HandSize returns the number of cards in player's hand.
+func (t *Tracker) HandSize(player int) int { return t.hands[player] }
+
+// Possible returns the cards that may be in player's hand, in ascending order.
+// For the Player's own position, these are the cards in its hand. For another
+// player holding cards, they are the unseen cards, and for a player holding
+// none, there are none.
+func (t *Tracker) Possible(player int) []card.Card {
+	switch {
+	case player == t.pos:
+		return t.hand.Cards()
+	case t.hands[player] == 0:
+		return nil
+	}
+	return t.Unseen()
+}
+
+// Keep returns the cards player has captured.
+func (t *Tracker) Keep(player int) []card.Card {
+	return append([]card.Card(nil), t.keep[player]...)
+}
+
+// Sweeps returns the number of sweeps player has made.
+func (t *Tracker) Sweeps(player int) int { return t.sweeps[player] }
+
+// LastCapture returns the player who played the most recent capture, or the
+// dealer if no player has captured.
+func (t *Tracker) LastCapture() int { return t.lastCapture }
+
+// Remaining returns the cards that have not been captured, in ascending
+// order: those on the table, in the players' hands, and in the deck.
+func (t *Tracker) Remaining() []card.Card { return t.remaining().Cards() }
+
+// remaining returns the cards that have not been captured.
+func (t *Tracker) remaining() card.Set {
+	s := card.All
+	for _, k := range t.keep {
+		s = s.Difference(card.NewSet(k...))
+	}
+	return s
+}
+
+// RemainingSpades returns the number of spades that have not been captured.
+func (t *Tracker) RemainingSpades() int { return t.remaining().Spades().Count() }
+
+// RemainingAces returns the number of aces that have not been captured.
+func (t *Tracker) RemainingAces() int { return t.remaining().Aces().Count() }
+
+// RemainingCassinos returns Big Cassino and Little Cassino, if they have not
+// been captured.
+func (t *Tracker) RemainingCassinos() []card.Card {
+	var cards []card.Card
+	for _, c := range t.Remaining() {
+		if c == card.LittleCassino || c == card.BigCassino {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// Race returns the number of cards each side has captured. In a partnership
+// game, there are two sides: players 0 and 2, and players 1 and 3.
+// Otherwise each player is a side.
+func (t *Tracker) Race() []int {
+	race := make([]int, t.sides())
+	for i, k := range t.keep {
+		race[t.side(i)] += len(k)
+	}
+	return race
+}
+
+// MostCards returns the side that has secured the points for most cards,
+// and reports whether any side has: its captures exceed those of every other
+// side even if that side captures all the cards remaining. Between two sides,
+// this is the first to capture 27 cards.
+func (t *Tracker) MostCards() (int, bool) {
+	race := t.Race()
+	remaining := 52
+	for _, n := range race {
+		remaining -= n
+	}
+	for i, n := range race {
+		secured := true
+		for j, m := range race {
+			if j != i && m+remaining >= n {
+				secured = false
+			}
+		}
+		if secured {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// sides returns the number of sides in the game.
+func (t *Tracker) sides() int {
+	if t.partnerships {
+		return 2
+	}
+	return t.players
+}
+
+// side returns the side a player scores for.
+func (t *Tracker) side(player int) int {
+	if t.partnerships {
+		return player % 2
+	}
+	return player
+}
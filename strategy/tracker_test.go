@@ -0,0 +1,243 @@
+package strategy
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dkmccandless/cassino/card"
+	"github.com/dkmccandless/cassino/game"
+)
+
+// A tracked Player checks its Tracker against the game it plays. The game
+// informs it of each turn with NoteTurn.
+type tracked struct {
+	*Tracker
+	p *Heuristic
+	t *testing.T
+}
+
+func (tr *tracked) Init(pos, dealer int, piles map[int]game.Pile) {
+	tr.Tracker.Init(pos, dealer, piles)
+	tr.p.Init(pos, dealer, piles)
+}
+
+func (tr *tracked) Hand(hand []card.Card) {
+	tr.Tracker.Hand(hand)
+	tr.p.Hand(hand)
+}
+
+func (tr *tracked) Play(piles map[int]game.Pile) game.Action {
+	check(tr.t, tr.Tracker, tr.p)
+	return tr.p.Play(piles)
+}
+
+// A noted Player is a tracked Player that is not a game.TurnNoter. It forwards
+// its Note calls to its Tracker and reports its own Actions with NotePlay.
+type noted struct {
+	tr *Tracker
+	p  *Heuristic
+	t  *testing.T
+}
+
+func (n *noted) Init(pos, dealer int, piles map[int]game.Pile) {
+	n.tr.Init(pos, dealer, piles)
+	n.p.Init(pos, dealer, piles)
+}
+
+func (n *noted) Hand(hand []card.Card) {
+	n.tr.Hand(hand)
+	n.p.Hand(hand)
+}
+
+func (n *noted) Note(played card.Card, captured []card.Card) {
+	n.tr.Note(played, captured)
+	n.p.Note(played, captured)
+}
+
+func (n *noted) EndHand(deck int) { n.tr.EndHand(deck) }
+
+func (n *noted) Play(piles map[int]game.Pile) game.Action {
+	check(n.t, n.tr, n.p)
+	a := n.p.Play(piles)
+	n.tr.NotePlay(a, piles)
+	return a
+}
+
+// check checks tr against the hand of p, the Player it tracks for, and the
+// cards it has not seen.
+func check(t *testing.T, tr *Tracker, p *Heuristic) {
+	if tr.turn != tr.pos {
+		t.Errorf("player %v: turn = %v", tr.pos, tr.turn)
+	}
+	n := tr.Deck()
+	for i := 0; i < tr.players; i++ {
+		if i == tr.pos {
+			continue
+		}
+		n += tr.HandSize(i)
+		if got := len(tr.Possible(i)); tr.HandSize(i) == 0 && got != 0 {
+			t.Errorf("player %v: %v possible cards for player %v, who holds none", tr.pos, got, i)
+		}
+	}
+	if u := tr.Unseen(); len(u) != n {
+		t.Errorf("player %v: %v unseen cards, expected %v", tr.pos, len(u), n)
+	}
+	if got, want := tr.Possible(tr.pos), sortCards(append([]card.Card(nil), p.hand...)); !reflect.DeepEqual(got, want) {
+		t.Errorf("player %v: Possible = %v, expected %v", tr.pos, got, want)
+	}
+	if got := tr.HandSize(tr.pos); got != len(p.hand) {
+		t.Errorf("player %v: HandSize = %v, expected %v", tr.pos, got, len(p.hand))
+	}
+}
+
+func TestTracker(t *testing.T) {
+	for name, rules := range map[string]game.Rules{
+		"basic": {},
+		"draw":  {Draw: true},
+	} {
+		for n := 2; n <= 4; n++ {
+			for seed := int64(1); seed <= 5; seed++ {
+				for _, note := range []bool{false, true} {
+					trs := make([]*Tracker, n)
+					players := make([]game.Player, n)
+					for i := range players {
+						trs[i] = NewTracker(rules, n, false)
+						if note {
+							players[i] = &noted{tr: trs[i], p: NewHeuristic(rules), t: t}
+						} else {
+							players[i] = &tracked{Tracker: trs[i], p: NewHeuristic(rules), t: t}
+						}
+					}
+					r, err := game.PlayGame(game.Options{Seed: seed, Rules: rules}, players...)
+					if err != nil {
+						t.Fatalf("PlayGame(%q, %v players, seed %v): %v", name, n, seed, err)
+					}
+					for pos, tr := range trs {
+						if tr.Deck() != 0 || len(tr.Unseen()) != 0 {
+							t.Errorf("%q, %v players, seed %v, note %v, player %v: Deck = %v, Unseen = %v at end of game",
+								name, n, seed, note, pos, tr.Deck(), tr.Unseen())
+						}
+						if tr.LastCapture() != r.LastCapture {
+							t.Errorf("%q, %v players, seed %v, note %v, player %v: LastCapture = %v, expected %v",
+								name, n, seed, note, pos, tr.LastCapture(), r.LastCapture)
+						}
+						for i := range players {
+							if got, want := sortCards(tr.Keep(i)), sortCards(append([]card.Card(nil), r.Keep[i]...)); !reflect.DeepEqual(got, want) {
+								t.Errorf("%q, %v players, seed %v, note %v, player %v: Keep(%v) = %v, expected %v",
+									name, n, seed, note, pos, i, got, want)
+							}
+							if got, want := tr.Sweeps(i), r.Breakdown[i].Sweeps; got != want {
+								t.Errorf("%q, %v players, seed %v, note %v, player %v: Sweeps(%v) = %v, expected %v",
+									name, n, seed, note, pos, i, got, want)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTrackerRemaining(t *testing.T) {
+	tr := NewTracker(game.Rules{}, 2, false)
+	tr.Init(0, 1, map[int]game.Pile{
+		1: {Cards: []card.Card{0}, Value: 1},   // ♣A
+		2: {Cards: []card.Card{7}, Value: 2},   // ♠2
+		3: {Cards: []card.Card{37}, Value: 10}, // ♦10
+		4: {Cards: []card.Card{51}},            // ♠K
+	})
+	tr.Hand([]card.Card{3, 4, 5, 6}) // ♠A ♣2 ♦2 ♥2
+	tr.NoteTurn(game.Turn{Player: 0, Action: game.Action{Card: 3, Sets: [][]int{{1}}}, Captured: []card.Card{0, 3}})
+	tr.NoteTurn(game.Turn{Player: 1, Action: game.Action{Card: 39, Sets: [][]int{{3}}}, Captured: []card.Card{37, 39}})
+
+	if got, want := tr.RemainingAces(), 2; got != want {
+		t.Errorf("RemainingAces() = %v, expected %v", got, want)
+	}
+	if got, want := tr.RemainingSpades(), 11; got != want {
+		t.Errorf("RemainingSpades() = %v, expected %v", got, want)
+	}
+	if got, want := tr.RemainingCassinos(), []card.Card{card.LittleCassino}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemainingCassinos() = %v, expected %v", got, want)
+	}
+	if got, want := tr.Race(), []int{2, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Race() = %v, expected %v", got, want)
+	}
+	if got, want := len(tr.Possible(1)), 52-4-4-1; got != want {
+		t.Errorf("len(Possible(1)) = %v, expected %v", got, want)
+	}
+	if got, want := tr.HandSize(1), 3; got != want {
+		t.Errorf("HandSize(1) = %v, expected %v", got, want)
+	}
+}
+
+func TestTrackerMostCards(t *testing.T) {
+	for _, test := range []struct {
+		players      int
+		partnerships bool
+		keeps        []int
+		side         int
+		ok           bool
+	}{
+		{2, false, []int{0, 0}, 0, false},
+		{2, false, []int{26, 10}, 0, false},
+		{2, false, []int{10, 27}, 1, true},
+		{3, false, []int{20, 10, 5}, 0, false},
+		{3, false, []int{25, 10, 10}, 0, true},
+		{4, true, []int{14, 0, 13, 0}, 0, true},
+		{4, true, []int{13, 0, 13, 0}, 0, false},
+	} {
+		tr := NewTracker(game.Rules{}, test.players, test.partnerships)
+		tr.Init(0, 0, nil)
+		c := card.Card(0)
+		for i, n := range test.keeps {
+			for k := 0; k < n; k++ {
+				tr.keep[i] = append(tr.keep[i], c)
+				c++
+			}
+		}
+		if side, ok := tr.MostCards(); side != test.side || ok != test.ok {
+			t.Errorf("MostCards() with %v = %v, %v; expected %v, %v", test.keeps, side, ok, test.side, test.ok)
+		}
+	}
+}
+
+func TestTrackerNote(t *testing.T) {
+	tr := NewTracker(game.Rules{}, 2, false)
+	tr.Init(1, 1, map[int]game.Pile{1: {Cards: []card.Card{6}, Value: 2}}) // ♥2
+	tr.Hand([]card.Card{0, 1, 2, 3})                                       // ♣A ♦A ♥A ♠A
+	tr.Note(5, []card.Card{6, 5})                                          // ♦2 takes ♥2
+	if !tr.Seen(5) || !tr.Seen(6) || tr.Seen(7) {
+		t.Errorf("Seen after Note: %v, %v, %v", tr.Seen(5), tr.Seen(6), tr.Seen(7))
+	}
+	if got, want := tr.HandSize(0), 3; got != want {
+		t.Errorf("HandSize(0) = %v, expected %v", got, want)
+	}
+	if got, want := tr.Keep(0), []card.Card{6, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keep(0) = %v, expected %v", got, want)
+	}
+	if got, want := tr.Sweeps(0), 1; got != want {
+		t.Errorf("Sweeps(0) = %v, expected %v", got, want)
+	}
+	tr.NotePlay(game.Action{Card: 3}, nil)
+	if got, want := tr.HandSize(1), 3; got != want {
+		t.Errorf("HandSize(1) = %v, expected %v", got, want)
+	}
+	if got, want := tr.LastCapture(), 0; got != want {
+		t.Errorf("LastCapture() = %v, expected %v", got, want)
+	}
+	tr.Note(7, nil)
+	tr.NotePlay(game.Action{Card: 2, Sets: [][]int{{1}}}, map[int]game.Pile{1: {Cards: []card.Card{3}, Value: 1}})
+	if got, want := tr.Keep(1), []card.Card{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keep(1) = %v, expected %v", got, want)
+	}
+	if got, want := tr.Sweeps(1), 0; got != want {
+		t.Errorf("Sweeps(1) = %v, expected %v", got, want)
+	}
+}
+
+// sortCards sorts cards in ascending order and returns them.
+func sortCards(cards []card.Card) []card.Card {
+	sort.Slice(cards, func(i, j int) bool { return cards[i] < cards[j] })
+	return cards
+}
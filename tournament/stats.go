@@ -0,0 +1,77 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+)
+
+// standings summarizes each entrant's results in games.
+func standings(opts Options, entrants []Entrant, games []Game) []Standing {
+	ss := make([]Standing, len(entrants))
+	diffs := make([][]float64, len(entrants))
+	for i, e := range entrants {
+		ss[i] = Standing{Name: e.Name, Elo: 1500}
+	}
+	for _, g := range games {
+		a, b := g.Entrants[0], g.Entrants[1]
+		var s float64
+		switch {
+		case g.Score[0] > g.Score[1]:
+			s = 1
+			ss[a].Wins++
+			ss[b].Losses++
+		case g.Score[0] < g.Score[1]:
+			ss[a].Losses++
+			ss[b].Wins++
+		default:
+			s = 0.5
+			ss[a].Draws++
+			ss[b].Draws++
+		}
+		ss[a].Games++
+		ss[b].Games++
+		d := float64(g.Score[0] - g.Score[1])
+		diffs[a] = append(diffs[a], d)
+		diffs[b] = append(diffs[b], -d)
+
+		// Ratings are updated in schedule order, so they do not depend on
+		// the order in which concurrent games finish.
+		delta := opts.K * (s - expected(ss[a].Elo, ss[b].Elo))
+		ss[a].Elo += delta
+		ss[b].Elo -= delta
+	}
+	for i := range ss {
+		s := &ss[i]
+		if s.Games == 0 {
+			continue
+		}
+		s.WinRate = (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games)
+		s.MeanDiff, s.CI = meanCI(diffs[i])
+	}
+	sort.SliceStable(ss, func(i, j int) bool { return ss[i].Elo > ss[j].Elo })
+	return ss
+}
+
+// expected returns the expected score of a player rated a against a player
+// rated b.
+func expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// meanCI returns the mean of xs and the half-width of its 95% confidence
+// interval under the normal approximation.
+func meanCI(xs []float64) (mean, ci float64) {
+	n := float64(len(xs))
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= n
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, 1.96 * math.Sqrt(ss/(n-1)/n)
+}
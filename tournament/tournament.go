@@ -0,0 +1,296 @@
+// Package tournament runs tournaments among computer Players for Cassino.
+//
+// Entrants meet in pairings of two-player games. Each pairing plays a number
+// of deals twice, with the seats swapped, so that each entrant plays each
+// deck from both sides.
+package tournament
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/dkmccandless/cassino/game"
+)
+
+// An Entrant is a Player registered in a tournament.
+type Entrant struct {
+	// Name identifies the Entrant.
+	Name string
+
+	// New returns a new instance of the Entrant's Player. Each game uses a
+	// new instance. Games are played concurrently, so New may be called
+	// concurrently.
+	New func() game.Player
+}
+
+// A Format determines how entrants are paired.
+type Format int
+
+const (
+	// RoundRobin pairs every entrant with every other entrant once.
+	RoundRobin Format = iota
+
+	// Swiss pairs entrants over a number of rounds. In each round, entrants
+	// with similar records meet, avoiding rematches where possible.
+	Swiss
+)
+
+// Options configures a tournament.
+type Options struct {
+	// Format determines how entrants are paired.
+	Format Format
+
+	// Rounds is the number of rounds of a Swiss tournament. If zero, it is
+	// the base-2 logarithm of the number of entrants, rounded up.
+	Rounds int
+
+	// Deals is the number of deals each pairing plays. Each deal is played
+	// twice with the seats swapped. If zero, it is 10.
+	Deals int
+
+	// Rules selects the variant of Cassino to play.
+	Rules game.Rules
+
+	// Workers is the number of games played concurrently. If zero, it is
+	// runtime.GOMAXPROCS(0).
+	Workers int
+
+	// K is the Elo K-factor. If zero, it is 16.
+	K float64
+
+	// Seed, if nonzero, seeds the sequence of deals. If Seed is zero, a
+	// random Seed is chosen.
+	Seed int64
+}
+
+// A Game records the outcome of a game in a tournament.
+type Game struct {
+	// Round is the round in which the game was played.
+	Round int
+
+	// Entrants lists the indexes of the entrants in seats 0 and 1.
+	Entrants [2]int
+
+	// Seed is the Seed used to shuffle the deck.
+	Seed int64
+
+	// Dealer is the seat of the dealer.
+	Dealer int
+
+	// Score records the score of each seat.
+	Score [2]int
+}
+
+// A Standing summarizes an entrant's results.
+type Standing struct {
+	// Name identifies the entrant.
+	Name string
+
+	// Games is the number of games played.
+	Games int
+
+	// Wins, Losses, and Draws count the games won, lost, and drawn.
+	Wins, Losses, Draws int
+
+	// WinRate is the fraction of games won, counting draws as half a win.
+	WinRate float64
+
+	// MeanDiff is the mean score differential per game.
+	MeanDiff float64
+
+	// CI is the half-width of the 95% confidence interval of MeanDiff.
+	CI float64
+
+	// Elo is the entrant's Elo rating, starting from 1500.
+	Elo float64
+}
+
+// A Result describes the outcome of a tournament.
+type Result struct {
+	// Standings lists each entrant's results, in descending order of Elo.
+	Standings []Standing
+
+	// Games lists the games in the order they were scheduled.
+	Games []Game
+
+	// Seed is the Seed used to choose the deals.
+	Seed int64
+}
+
+// Run runs a tournament among entrants according to opts and returns the
+// result. If a game ends with an error, Run returns the error.
+func Run(opts Options, entrants ...Entrant) (Result, error) {
+	if len(entrants) < 2 {
+		return Result{}, fmt.Errorf("invalid number of entrants %v", len(entrants))
+	}
+	if opts.Deals == 0 {
+		opts.Deals = 10
+	}
+	switch {
+	case opts.Workers < 0:
+		return Result{}, fmt.Errorf("invalid number of workers %v", opts.Workers)
+	case opts.Workers == 0:
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.K == 0 {
+		opts.K = 16
+	}
+	r := Result{Seed: opts.Seed}
+	for r.Seed == 0 {
+		r.Seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(r.Seed))
+
+	switch opts.Format {
+	case RoundRobin:
+		var pairs [][2]int
+		for i := range entrants {
+			for j := i + 1; j < len(entrants); j++ {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+		games, err := play(opts, entrants, schedule(opts, rng, 0, pairs))
+		if err != nil {
+			return Result{}, err
+		}
+		r.Games = games
+	case Swiss:
+		rounds := opts.Rounds
+		if rounds == 0 {
+			rounds = bits.Len(uint(len(entrants) - 1))
+		}
+		met := make(map[[2]int]bool)
+		for n := 0; n < rounds; n++ {
+			pairs := swissPairs(len(entrants), r.Games, met)
+			games, err := play(opts, entrants, schedule(opts, rng, n, pairs))
+			if err != nil {
+				return Result{}, err
+			}
+			r.Games = append(r.Games, games...)
+		}
+	default:
+		return Result{}, fmt.Errorf("invalid format %v", opts.Format)
+	}
+	r.Standings = standings(opts, entrants, r.Games)
+	return r, nil
+}
+
+// schedule returns the games to be played by pairs in a round.
+func schedule(opts Options, rng *rand.Rand, round int, pairs [][2]int) []Game {
+	var games []Game
+	for _, p := range pairs {
+		for d := 0; d < opts.Deals; d++ {
+			seed := rng.Int63()
+			for seed == 0 {
+				seed = rng.Int63()
+			}
+			games = append(games,
+				Game{Round: round, Entrants: [2]int{p[0], p[1]}, Seed: seed, Dealer: d % 2},
+				Game{Round: round, Entrants: [2]int{p[1], p[0]}, Seed: seed, Dealer: d % 2},
+			)
+		}
+	}
+	return games
+}
+
+// play plays games concurrently and returns them with their scores.
+func play(opts Options, entrants []Entrant, games []Game) ([]Game, error) {
+	jobs := make(chan int)
+	errs := make([]error, len(games))
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				g := &games[i]
+				res, err := game.PlayGame(
+					game.Options{Seed: g.Seed, Dealer: g.Dealer, Rules: opts.Rules},
+					entrants[g.Entrants[0]].New(), entrants[g.Entrants[1]].New(),
+				)
+				if err != nil {
+					errs[i] = fmt.Errorf("%v vs. %v, seed %v: %w",
+						entrants[g.Entrants[0]].Name, entrants[g.Entrants[1]].Name, g.Seed, err)
+					continue
+				}
+				g.Score = [2]int{res.Score[0], res.Score[1]}
+			}
+		}()
+	}
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return games, nil
+}
+
+// swissPairs pairs n entrants in order of the points they have won in games,
+// pairing each with the next available entrant it has not met. met records
+// the pairs that have met and is updated with the new pairs. If n is odd, the
+// entrant left unpaired sits out the round.
+func swissPairs(n int, games []Game, met map[[2]int]bool) [][2]int {
+	points := make([]float64, n)
+	for _, g := range games {
+		switch {
+		case g.Score[0] > g.Score[1]:
+			points[g.Entrants[0]]++
+		case g.Score[0] < g.Score[1]:
+			points[g.Entrants[1]]++
+		default:
+			points[g.Entrants[0]] += 0.5
+			points[g.Entrants[1]] += 0.5
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return points[order[i]] > points[order[j]] })
+
+	paired := make([]bool, n)
+	var pairs [][2]int
+	for k, i := range order {
+		if paired[i] {
+			continue
+		}
+		// Prefer an opponent not yet met; otherwise take the next available.
+		opp := -1
+		for _, j := range order[k+1:] {
+			if paired[j] {
+				continue
+			}
+			if !met[key(i, j)] {
+				opp = j
+				break
+			}
+			if opp < 0 {
+				opp = j
+			}
+		}
+		if opp < 0 {
+			break
+		}
+		paired[i], paired[opp] = true, true
+		met[key(i, opp)] = true
+		pairs = append(pairs, [2]int{i, opp})
+	}
+	return pairs
+}
+
+// key returns a pair of entrants in ascending order.
+func key(i, j int) [2]int {
+	if i > j {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
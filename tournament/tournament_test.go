@@ -0,0 +1,141 @@
+package tournament
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dkmccandless/cassino/game"
+	"github.com/dkmccandless/cassino/strategy"
+)
+
+func entrants() []Entrant {
+	var n int64
+	return []Entrant{
+		{"random", func() game.Player {
+			return strategy.NewRandom(game.Rules{}, rand.NewSource(atomic.AddInt64(&n, 1)))
+		}},
+		{"greedy", func() game.Player { return strategy.NewGreedy(game.Rules{}) }},
+		{"heuristic", func() game.Player { return strategy.NewHeuristic(game.Rules{}) }},
+	}
+}
+
+func TestRunRoundRobin(t *testing.T) {
+	r, err := Run(Options{Deals: 4, Seed: 1, Workers: 1}, entrants()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(r.Games), 3*4*2; got != want {
+		t.Fatalf("got %v games, expected %v", got, want)
+	}
+	for i := 0; i < len(r.Games); i += 2 {
+		a, b := r.Games[i], r.Games[i+1]
+		if a.Seed != b.Seed || a.Dealer != b.Dealer || a.Entrants != [2]int{b.Entrants[1], b.Entrants[0]} {
+			t.Errorf("games %v and %v are not mirrored: %+v, %+v", i, i+1, a, b)
+		}
+	}
+	var elo float64
+	for _, s := range r.Standings {
+		if s.Games != 16 || s.Wins+s.Losses+s.Draws != s.Games {
+			t.Errorf("%v: %+v", s.Name, s)
+		}
+		elo += s.Elo
+	}
+	if math.Abs(elo-3*1500) > 1e-9 {
+		t.Errorf("total Elo %v, expected %v", elo, 3*1500)
+	}
+	if r.Standings[len(r.Standings)-1].Name != "random" {
+		t.Errorf("standings %+v: random is not last", r.Standings)
+	}
+}
+
+func TestRunConcurrent(t *testing.T) {
+	r1, err := Run(Options{Deals: 3, Seed: 2, Workers: 1}, entrants()[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r4, err := Run(Options{Deals: 3, Seed: 2, Workers: 4}, entrants()[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r1, r4) {
+		t.Errorf("results differ with 1 and 4 workers:\n%+v\n%+v", r1, r4)
+	}
+}
+
+func TestRunSwiss(t *testing.T) {
+	es := append(entrants()[1:], entrants()[1:]...)
+	es = append(es, es[0])
+	r, err := Run(Options{Format: Swiss, Deals: 2, Seed: 3}, es...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Five entrants play three rounds of two pairings.
+	if got, want := len(r.Games), 3*2*2*2; got != want {
+		t.Errorf("got %v games, expected %v", got, want)
+	}
+	met := make(map[[2]int]int)
+	for _, g := range r.Games {
+		met[key(g.Entrants[0], g.Entrants[1])]++
+	}
+	for p, n := range met {
+		if n != 4 {
+			t.Errorf("pair %v played %v games, expected 4", p, n)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(Options{}, entrants()[0]); err == nil {
+		t.Error("Run with one entrant: got nil error")
+	}
+	if _, err := Run(Options{Format: Format(9)}, entrants()...); err == nil {
+		t.Error("Run with invalid format: got nil error")
+	}
+	if _, err := Run(Options{Workers: -1}, entrants()...); err == nil {
+		t.Error("Run with negative workers: got nil error")
+	}
+}
+
+func TestSwissPairs(t *testing.T) {
+	games := []Game{
+		{Entrants: [2]int{0, 1}, Score: [2]int{5, 6}},
+		{Entrants: [2]int{2, 3}, Score: [2]int{7, 4}},
+	}
+	met := map[[2]int]bool{{0, 1}: true, {2, 3}: true}
+	got := swissPairs(4, games, met)
+	want := [][2]int{{1, 2}, {0, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("swissPairs = %v, expected %v", got, want)
+	}
+}
+
+func TestMeanCI(t *testing.T) {
+	for _, test := range []struct {
+		xs       []float64
+		mean, ci float64
+	}{
+		{[]float64{3}, 3, 0},
+		{[]float64{1, 1, 1, 1}, 1, 0},
+		{[]float64{-2, 2}, 0, 1.96 * 2},
+	} {
+		mean, ci := meanCI(test.xs)
+		if math.Abs(mean-test.mean) > 1e-9 || math.Abs(ci-test.ci) > 1e-9 {
+			t.Errorf("meanCI(%v) = %v, %v; expected %v, %v", test.xs, mean, ci, test.mean, test.ci)
+		}
+	}
+}
+
+func TestExpected(t *testing.T) {
+	for _, test := range []struct{ a, b, e float64 }{
+		{1500, 1500, 0.5},
+		{1900, 1500, 10.0 / 11},
+		{1500, 1900, 1.0 / 11},
+	} {
+		if e := expected(test.a, test.b); math.Abs(e-test.e) > 1e-9 {
+			t.Errorf("expected(%v, %v) = %v, expected %v", test.a, test.b, e, test.e)
+		}
+	}
+}